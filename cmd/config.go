@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/gardenlinux/glci/internal/glci"
@@ -30,18 +31,10 @@ func loadConfigAndCredentials(ctx context.Context, cfg *viper.Viper) (glci.Flavo
 		return glci.FlavorsConfig{}, glci.PublishingConfig{}, nil, nil, fmt.Errorf("invalid flavors configuration: %w", err)
 	}
 
-	pcfg := cfg.Sub("publishing")
-	if pcfg == nil {
-		return glci.FlavorsConfig{}, glci.PublishingConfig{}, nil, nil, errors.New("missing publishing configuration")
-	}
 	var publishingCfg glci.PublishingConfig
-	err = pcfg.Unmarshal(&publishingCfg)
+	publishingCfg, err = loadPublishingConfig(cfg)
 	if err != nil {
-		return glci.FlavorsConfig{}, glci.PublishingConfig{}, nil, nil, fmt.Errorf("invalid publishing configuration: %w", err)
-	}
-	err = publishingCfg.Validate()
-	if err != nil {
-		return glci.FlavorsConfig{}, glci.PublishingConfig{}, nil, nil, fmt.Errorf("invalid publishing configuration: %w", err)
+		return glci.FlavorsConfig{}, glci.PublishingConfig{}, nil, nil, err
 	}
 
 	acfg := cfg.Sub("aliases")
@@ -65,3 +58,49 @@ func loadConfigAndCredentials(ctx context.Context, cfg *viper.Viper) (glci.Flavo
 
 	return flavorsCfg, publishingCfg, aliasesCfg, creds, nil
 }
+
+func loadPublishingConfig(cfg *viper.Viper) (glci.PublishingConfig, error) {
+	pcfg := cfg.Sub("publishing")
+	if pcfg == nil {
+		return glci.PublishingConfig{}, errors.New("missing publishing configuration")
+	}
+	var publishingCfg glci.PublishingConfig
+	err := pcfg.Unmarshal(&publishingCfg)
+	if err != nil {
+		return glci.PublishingConfig{}, fmt.Errorf("invalid publishing configuration: %w", err)
+	}
+	err = publishingCfg.Validate()
+	if err != nil {
+		return glci.PublishingConfig{}, fmt.Errorf("invalid publishing configuration: %w", err)
+	}
+
+	return publishingCfg, nil
+}
+
+func options(cfg *viper.Viper) glci.Options {
+	return glci.Options{
+		Parallelism:       cfg.GetInt("parallelism"),
+		KeepGoing:         cfg.GetBool("keep-going"),
+		PartialDescriptor: cfg.GetBool("partial-descriptor"),
+		KeepLeftovers:     cfg.GetBool("keep-leftovers"),
+		Selection:         selection(cfg),
+	}
+}
+
+func selection(cfg *viper.Viper) glci.Selection {
+	return glci.Selection{
+		Flavors:   cfg.GetStringSlice("flavor"),
+		Platforms: cfg.GetStringSlice("platform"),
+		Targets:   cfg.GetStringSlice("target"),
+		Clouds:    cfg.GetStringSlice("cloud"),
+		Regions:   cfg.GetStringSlice("region"),
+	}
+}
+
+func addSelectionFlags(c *cobra.Command) {
+	c.Flags().StringSlice("flavor", nil, "only process flavors whose cname matches one of these glob patterns")
+	c.Flags().StringSlice("platform", nil, "only process flavors of these platforms")
+	c.Flags().StringSlice("target", nil, "only process these publishing target types")
+	c.Flags().StringSlice("cloud", nil, "only process these clouds, such as public or China")
+	c.Flags().StringSlice("region", nil, "only process these regions, in addition to the regions images are imported into")
+}
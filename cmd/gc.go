@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+func gcCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "gc",
+		Short: "Find and delete resources on cloud providers which have been created by GLCI but are not referenced by any manifest",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(gc),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().Duration("min-age", 24*time.Hour, "minimum age of resources to consider, protects publications in progress")
+	c.Flags().Bool("confirm", false, "delete the orphaned resources instead of only listing them")
+	c.Flags().StringP("output", "o", "table", "output format (table, yaml or json)")
+
+	return c
+}
+
+func gc(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	_, publishingCfg, _, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	orphans, gcErr := glci.GC(ctx, publishingCfg, creds, cfg.GetDuration("min-age"), cfg.GetBool("confirm"))
+	if orphans != nil || gcErr == nil {
+		err = printOrphans(cfg.GetString("output"), orphans)
+		if err != nil {
+			return err
+		}
+	}
+
+	return gcErr //nolint:wrapcheck // Directly wraps the GLCI command.
+}
+
+func printOrphans(format string, orphans []glci.Orphan) error {
+	return printOutput(format, orphans, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "TARGET\tKIND\tCLOUD\tREGION\tID\tNAME\tCREATED\tDELETED")
+		if err != nil {
+			return fmt.Errorf("cannot write orphans: %w", err)
+		}
+		for _, o := range orphans {
+			_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%t\n", o.Target, o.Kind, orDash(o.Cloud), orDash(o.Region), o.ID,
+				orDash(o.Name), o.Created.Format(time.RFC3339), o.Deleted)
+			if err != nil {
+				return fmt.Errorf("cannot write orphans: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
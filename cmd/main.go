@@ -25,7 +25,16 @@ func main() {
 		c.PersistentFlags().Bool("dev", false, "run in development mode")
 		c.PersistentFlags().String("config-file", "", "path to configuration file")
 		c.AddCommand(publishCmd())
+		c.AddCommand(promoteCmd())
 		c.AddCommand(removeCmd())
+		c.AddCommand(pruneCmd())
+		c.AddCommand(statusCmd())
+		c.AddCommand(verifyCmd())
+		c.AddCommand(gcCmd())
+		c.AddCommand(unlockCmd())
+		c.AddCommand(serveCmd())
+		c.AddCommand(watchCmd())
+		c.AddCommand(manifestCmd())
 	})
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+func manifestCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "manifest",
+		Short: "Maintain the release manifests of Garden Linux",
+		Args:  cobra.NoArgs,
+	}
+
+	c.AddCommand(manifestMigrateCmd())
+	c.AddCommand(manifestIndexCmd())
+
+	return c
+}
+
+func manifestMigrateCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade release manifests written by older versions of GLCI to the current manifest schema",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(manifestMigrate),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().Bool("dry-run", false, "only show what would be done")
+	c.Flags().Bool("backup", false, "copy each manifest beneath meta/backups/ before rewriting it")
+	c.Flags().StringP("output", "o", "table", "output format (table, yaml or json)")
+
+	return c
+}
+
+func manifestMigrate(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	_, publishingCfg, _, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	var migrated []glci.MigratedManifest
+	migrated, err = glci.MigrateManifests(ctx, publishingCfg, creds, cfg.GetBool("dry-run"), cfg.GetBool("backup"))
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the GLCI command.
+	}
+
+	return printMigratedManifests(cfg.GetString("output"), migrated)
+}
+
+func printMigratedManifests(format string, migrated []glci.MigratedManifest) error {
+	return printOutput(format, migrated, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "KEY\tFROM\tTO\tMIGRATIONS\tBACKUP\tSKIPPED")
+		if err != nil {
+			return fmt.Errorf("cannot write migrated manifests: %w", err)
+		}
+		for _, m := range migrated {
+			_, err = fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", m.Key, m.From, m.To, strings.Join(m.Migrations, ", "), m.Backup,
+				m.Skipped)
+			if err != nil {
+				return fmt.Errorf("cannot write migrated manifests: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func manifestIndexCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "index",
+		Short: "Rebuild the aggregated release indexes from the manifests of the flavors",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(manifestIndex),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().StringP("version", "v", "", "release version, all releases if omitted")
+	c.Flags().StringP("commit", "c", "", "release commit(ish), all commits of the version if omitted")
+
+	return c
+}
+
+func manifestIndex(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	_, publishingCfg, _, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	keys, err = glci.RebuildReleaseIndexes(ctx, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"))
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the GLCI command.
+	}
+	log.Info(ctx, "Release indexes rebuilt", "count", len(keys))
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	outputTable = "table"
+	outputYAML  = "yaml"
+	outputJSON  = "json"
+)
+
+func printOutput(format string, data any, table func(w io.Writer) error) error {
+	switch format {
+	case outputTable, "":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		err := table(w)
+		if err != nil {
+			return err
+		}
+		err = w.Flush()
+		if err != nil {
+			return fmt.Errorf("cannot write table: %w", err)
+		}
+	case outputYAML:
+		enc := yaml.NewEncoder(os.Stdout)
+		err := enc.Encode(data)
+		if err != nil {
+			return fmt.Errorf("cannot write YAML: %w", err)
+		}
+	case outputJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(data)
+		if err != nil {
+			return fmt.Errorf("cannot write JSON: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown output format %s", format)
+	}
+
+	return nil
+}
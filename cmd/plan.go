@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gardenlinux/glci/internal/glci"
+)
+
+func printPlan(format string, plan []glci.PlannedPublication) error {
+	return printOutput(format, plan, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "CNAME\tPLATFORM\tTARGET\tREASON")
+		if err != nil {
+			return fmt.Errorf("cannot write plan: %w", err)
+		}
+		for _, p := range plan {
+			_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Cname, p.Platform, p.Target, p.Reason)
+			if err != nil {
+				return fmt.Errorf("cannot write plan: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+func promoteCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "promote",
+		Short: "Publish a Garden Linux release by reusing the images published with another configuration",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(promote),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().String("from-config-file", "", "path to the configuration file the release has been published with")
+	c.Flags().String("from-credentials-file", "", "path to credentials YAML file for --from-config-file (defaults to --credentials-file)")
+	c.Flags().String("from-credentials-base64", "", "base64 encoded credentials YAML for --from-config-file")
+	c.Flags().StringP("version", "v", "", "release version")
+	c.Flags().StringP("commit", "c", "", "release commit(ish), discovered from the manifests if omitted")
+	c.Flags().Bool("dry-run", false, "only show what would be done")
+	c.Flags().Int("parallelism", 1, "maximum number of publications to process concurrently")
+	c.Flags().Bool("keep-going", false, "continue with the remaining publications after a publication has failed")
+	c.Flags().Bool("partial-descriptor", false, "publish a component descriptor of the successful publications if some have failed")
+	c.Flags().Bool("keep-leftovers", false, "keep the resources of failed publications instead of rolling them back")
+	c.Flags().StringP("output", "o", "table", "output format of a dry run (table, yaml or json)")
+	addSelectionFlags(c)
+	addReportFlags(c)
+
+	return c
+}
+
+func promote(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	flavorsCfg, publishingCfg, aliasesCfg, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.GetBool("dry-run") {
+		var plan []glci.PlannedPublication
+		plan, err = glci.PlanPublish(ctx, flavorsCfg, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"),
+			selection(cfg))
+		if err != nil {
+			return err //nolint:wrapcheck // Directly wraps the GLCI command.
+		}
+
+		return printPlan(cfg.GetString("output"), plan)
+	}
+
+	fromCfgFile := cfg.GetString("from-config-file")
+	if fromCfgFile == "" {
+		return errors.New("missing --from-config-file")
+	}
+	fromCfg := viper.New()
+	fromCfg.SetConfigFile(fromCfgFile)
+	err = fromCfg.ReadInConfig()
+	if err != nil {
+		return fmt.Errorf("cannot read source config file: %w", err)
+	}
+	var fromPublishingCfg glci.PublishingConfig
+	fromPublishingCfg, err = loadPublishingConfig(fromCfg)
+	if err != nil {
+		return fmt.Errorf("invalid source configuration: %w", err)
+	}
+
+	fromCreds := creds
+	if cfg.GetString("from-credentials-file") != "" || cfg.GetString("from-credentials-base64") != "" {
+		fromCreds, err = glci.LoadCredentials(ctx, cfg.GetString("from-credentials-file"), cfg.GetString("from-credentials-base64"))
+		if err != nil {
+			return fmt.Errorf("cannot load source credentials: %w", err)
+		}
+	}
+
+	return reported(cfg, options(cfg), func(opts glci.Options) error {
+		//nolint:wrapcheck // Directly wraps the GLCI command.
+		return glci.Promote(ctx, flavorsCfg, fromPublishingCfg, fromCreds, publishingCfg, aliasesCfg, creds, cfg.GetString("version"),
+			cfg.GetString("commit"), opts)
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+func pruneCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "prune",
+		Short: "remove all Garden Linux releases which are not retained by the retention policy from cloud providers",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(prune),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().Bool("dry-run", false, "only show what would be done")
+	c.Flags().Int("parallelism", 1, "maximum number of publications to process concurrently")
+	c.Flags().Bool("keep-going", false, "continue with the remaining publications and releases after a publication has failed")
+	c.Flags().StringP("output", "o", "table", "output format of a dry run (table, yaml or json)")
+
+	return c
+}
+
+func prune(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	_, publishingCfg, _, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.GetBool("dry-run") {
+		var decisions []glci.RetentionDecision
+		decisions, err = glci.PlanPrune(ctx, publishingCfg, creds)
+		if err != nil {
+			return err //nolint:wrapcheck // Directly wraps the GLCI command.
+		}
+
+		return printRetention(cfg.GetString("output"), decisions)
+	}
+
+	return glci.Prune(ctx, publishingCfg, creds, options(cfg)) //nolint:wrapcheck // Directly wraps the GLCI command.
+}
+
+func printRetention(format string, decisions []glci.RetentionDecision) error {
+	return printOutput(format, decisions, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "VERSION\tCOMMIT\tPUBLISHED\tFLAVORS\tACTION\tREASON")
+		if err != nil {
+			return fmt.Errorf("cannot write retention plan: %w", err)
+		}
+		for _, d := range decisions {
+			action := "remove"
+			if d.Keep {
+				action = "keep"
+			}
+			_, err = fmt.Fprintf(w, "%s\t%.8s\t%s\t%s\t%s\t%s\n", d.Version, d.Commit, d.Published.Format(time.RFC3339),
+				strings.Join(d.Flavors, ","), action, d.Reason)
+			if err != nil {
+				return fmt.Errorf("cannot write retention plan: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -22,7 +22,15 @@ func publishCmd() *cobra.Command {
 	c.Flags().String("credentials-file", "", "path to credentials YAML file")
 	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
 	c.Flags().StringP("version", "v", "", "release version")
-	c.Flags().StringP("commit", "c", "", "release commit(ish)")
+	c.Flags().StringP("commit", "c", "", "release commit(ish), discovered from the manifests if omitted")
+	c.Flags().Bool("dry-run", false, "only show what would be done")
+	c.Flags().Int("parallelism", 1, "maximum number of publications to process concurrently")
+	c.Flags().Bool("keep-going", false, "continue with the remaining publications after a publication has failed")
+	c.Flags().Bool("partial-descriptor", false, "publish a component descriptor of the successful publications if some have failed")
+	c.Flags().Bool("keep-leftovers", false, "keep the resources of failed publications instead of rolling them back")
+	c.Flags().StringP("output", "o", "table", "output format of a dry run (table, yaml or json)")
+	addSelectionFlags(c)
+	addReportFlags(c)
 
 	return c
 }
@@ -36,6 +44,20 @@ func publish(ctx context.Context, cfg *viper.Viper) error {
 		return err
 	}
 
-	//nolint:wrapcheck // Directly wraps the GLCI command.
-	return glci.Publish(ctx, flavorsCfg, publishingCfg, aliasesCfg, creds, cfg.GetString("version"), cfg.GetString("commit"))
+	if cfg.GetBool("dry-run") {
+		var plan []glci.PlannedPublication
+		plan, err = glci.PlanPublish(ctx, flavorsCfg, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"),
+			selection(cfg))
+		if err != nil {
+			return err //nolint:wrapcheck // Directly wraps the GLCI command.
+		}
+
+		return printPlan(cfg.GetString("output"), plan)
+	}
+
+	return reported(cfg, options(cfg), func(opts glci.Options) error {
+		//nolint:wrapcheck // Directly wraps the GLCI command.
+		return glci.Publish(ctx, flavorsCfg, publishingCfg, aliasesCfg, creds, cfg.GetString("version"), cfg.GetString("commit"),
+			opts)
+	})
 }
@@ -22,7 +22,13 @@ func removeCmd() *cobra.Command {
 	c.Flags().String("credentials-file", "", "path to credentials YAML file")
 	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
 	c.Flags().StringP("version", "v", "", "release version")
-	c.Flags().StringP("commit", "c", "", "release commit(ish)")
+	c.Flags().StringP("commit", "c", "", "release commit(ish), discovered from the manifests if omitted")
+	c.Flags().Bool("dry-run", false, "only show what would be done")
+	c.Flags().Int("parallelism", 1, "maximum number of publications to process concurrently")
+	c.Flags().Bool("keep-going", false, "continue with the remaining publications after a publication has failed")
+	c.Flags().StringP("output", "o", "table", "output format of a dry run (table, yaml or json)")
+	addSelectionFlags(c)
+	addReportFlags(c)
 
 	return c
 }
@@ -36,6 +42,20 @@ func remove(ctx context.Context, cfg *viper.Viper) error {
 		return err
 	}
 
-	//nolint:wrapcheck // Directly wraps the GLCI command.
-	return glci.Remove(ctx, flavorsCfg, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"))
+	if cfg.GetBool("dry-run") {
+		var plan []glci.PlannedPublication
+		plan, err = glci.PlanRemove(ctx, flavorsCfg, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"),
+			selection(cfg))
+		if err != nil {
+			return err //nolint:wrapcheck // Directly wraps the GLCI command.
+		}
+
+		return printPlan(cfg.GetString("output"), plan)
+	}
+
+	return reported(cfg, options(cfg), func(opts glci.Options) error {
+		//nolint:wrapcheck // Directly wraps the GLCI command.
+		return glci.Remove(ctx, flavorsCfg, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"),
+			opts)
+	})
 }
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/glci"
+)
+
+const (
+	reportJSON     = "json"
+	reportMarkdown = "markdown"
+	reportJUnit    = "junit"
+)
+
+func addReportFlags(c *cobra.Command) {
+	c.Flags().String("report-file", "", "write a report of all considered publications to this file, also if the operation fails")
+	c.Flags().String("report-format", reportJSON, "format of the report (json, markdown or junit)")
+}
+
+// reported runs an operation and writes its report if one has been requested.
+func reported(cfg *viper.Viper, opts glci.Options, op func(opts glci.Options) error) error {
+	file := cfg.GetString("report-file")
+	if file == "" {
+		return op(opts)
+	}
+
+	var write func(w io.Writer, report *glci.Report) error
+	switch format := cfg.GetString("report-format"); format {
+	case reportJSON:
+		write = writeJSONReport
+	case reportMarkdown:
+		write = writeMarkdownReport
+	case reportJUnit:
+		write = writeJUnitReport
+	default:
+		return fmt.Errorf("unknown report format %s", format)
+	}
+
+	var report glci.Report
+	opts.Report = &report
+	err := op(opts)
+
+	return errors.Join(err, writeReport(file, &report, write))
+}
+
+func writeReport(file string, report *glci.Report, write func(w io.Writer, report *glci.Report) error) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("cannot create report file: %w", err)
+	}
+
+	err = write(f, report)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("cannot write report: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("cannot write report: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSONReport(w io.Writer, report *glci.Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report) //nolint:wrapcheck // Directly wraps the JSON encoder.
+}
+
+func writeMarkdownReport(w io.Writer, report *glci.Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## GLCI %s %s", report.Operation, report.Version)
+	if report.Commit != "" {
+		fmt.Fprintf(&b, " (%.8s)", report.Commit)
+	}
+	b.WriteString("\n\n")
+
+	counts := make(map[glci.ReportOutcome]int)
+	for _, entry := range report.Entries {
+		counts[entry.Outcome]++
+	}
+	fmt.Fprintf(&b, "%d published, %d removed, %d skipped, %d failed in %s\n\n", counts[glci.ReportOutcomePublished],
+		counts[glci.ReportOutcomeRemoved], counts[glci.ReportOutcomeSkipped], counts[glci.ReportOutcomeFailed],
+		report.Finished.Sub(report.Started).Round(time.Second))
+
+	if len(report.Entries) > 0 {
+		b.WriteString("| Flavor | Target | Outcome | Duration | Images |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, entry := range report.Entries {
+			outcome := string(entry.Outcome)
+			if entry.Reason != "" {
+				outcome += " (" + entry.Reason + ")"
+			}
+			images := make([]string, 0, len(entry.Images))
+			for _, image := range entry.Images {
+				images = append(images, "`"+imageLocation(image.Cloud, image.Region, image.ID)+"`")
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", markdownCell(entry.Cname), markdownCell(entry.Target), markdownCell(outcome),
+				reportDuration(entry), strings.Join(images, "<br>"))
+		}
+	}
+
+	for _, entry := range report.Entries {
+		if entry.Error == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s on %s failed\n\n```\n%s\n```\n", entry.Cname, entry.Target, entry.Error)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err //nolint:wrapcheck // Directly wraps the writer.
+}
+
+func imageLocation(cloud, region, id string) string {
+	var parts []string
+	if cloud != "" {
+		parts = append(parts, cloud)
+	}
+	if region != "" {
+		parts = append(parts, region)
+	}
+	if len(parts) == 0 {
+		return id
+	}
+	return strings.Join(parts, "/") + ": " + id
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
+
+func reportDuration(entry glci.ReportEntry) string {
+	if entry.Duration == 0 {
+		return "-"
+	}
+	return entry.Duration.Round(time.Second).String()
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      float64         `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Classname string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes the report as a JUnit XML test suite with each flavor and target as a test case, so that CI systems can show
+// the publications like tests.
+func writeJUnitReport(w io.Writer, report *glci.Report) error {
+	suite := junitTestSuite{
+		Name:      fmt.Sprintf("glci %s %s", report.Operation, report.Version),
+		Tests:     len(report.Entries),
+		Time:      report.Finished.Sub(report.Started).Seconds(),
+		Timestamp: report.Started.Format(time.RFC3339),
+		Cases:     make([]junitTestCase, 0, len(report.Entries)),
+	}
+	for _, entry := range report.Entries {
+		c := junitTestCase{
+			Classname: entry.Cname,
+			Name:      entry.Target,
+			Time:      entry.Duration.Seconds(),
+		}
+		switch entry.Outcome {
+		case glci.ReportOutcomeFailed:
+			suite.Failures++
+			c.Failure = &junitMessage{
+				Message: "failed",
+				Text:    entry.Error,
+			}
+		case glci.ReportOutcomeSkipped:
+			suite.Skipped++
+			c.Skipped = &junitMessage{
+				Message: entry.Reason,
+			}
+		case glci.ReportOutcomePublished, glci.ReportOutcomeRemoved:
+		}
+		images := make([]string, 0, len(entry.Images))
+		for _, image := range entry.Images {
+			images = append(images, imageLocation(image.Cloud, image.Region, image.ID))
+		}
+		c.SystemOut = strings.Join(images, "\n")
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the writer.
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(junitTestSuites{
+		Suites: []junitTestSuite{suite},
+	})
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the XML encoder.
+	}
+	_, err = io.WriteString(w, "\n")
+	return err //nolint:wrapcheck // Directly wraps the writer.
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+	"github.com/gardenlinux/glci/internal/server"
+)
+
+func serveCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve an HTTP API for running publish, remove and status jobs",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(serve),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().String("listen", ":8080", "address to listen on")
+	c.Flags().String("token", "", "bearer token required for every request (preferably set via GLCI_TOKEN)")
+
+	return c
+}
+
+func serve(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	flavorsCfg, publishingCfg, aliasesCfg, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	//nolint:wrapcheck // Directly wraps the server.
+	return server.Serve(ctx, cfg.GetString("listen"), cfg.GetString("token"), server.Config{
+		Flavors:    flavorsCfg,
+		Publishing: publishingCfg,
+		Aliases:    aliasesCfg,
+		Creds:      creds,
+		Dev:        cfg.GetBool("dev"),
+	})
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+)
+
+func statusCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "status",
+		Short: "Show the publication state of a Garden Linux release on cloud providers",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(status),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().StringP("version", "v", "", "release version")
+	c.Flags().StringP("commit", "c", "", "release commit(ish), discovered from the manifests if omitted")
+	c.Flags().StringP("output", "o", "table", "output format (table, yaml or json)")
+
+	return c
+}
+
+func status(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+
+	flavorsCfg, publishingCfg, _, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	var releaseStatus glci.ReleaseStatus
+	releaseStatus, err = glci.Status(ctx, flavorsCfg, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"))
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the GLCI command.
+	}
+
+	return printOutput(cfg.GetString("output"), releaseStatus, func(w io.Writer) error {
+		return printStatusTable(w, releaseStatus)
+	})
+}
+
+func printStatusTable(w io.Writer, releaseStatus glci.ReleaseStatus) error {
+	_, err := fmt.Fprintf(w, "CNAME\t%s\n", strings.Join(releaseStatus.Targets, "\t"))
+	if err != nil {
+		return fmt.Errorf("cannot write status: %w", err)
+	}
+	for _, flavor := range releaseStatus.Flavors {
+		cells := make([]string, 0, len(releaseStatus.Targets))
+		for _, target := range releaseStatus.Targets {
+			cells = append(cells, statusCell(flavor, target))
+		}
+		_, err = fmt.Fprintf(w, "%s\t%s\n", flavor.Cname, strings.Join(cells, "\t"))
+		if err != nil {
+			return fmt.Errorf("cannot write status: %w", err)
+		}
+	}
+
+	_, err = fmt.Fprintln(w, "\nCNAME\tTARGET\tCLOUD\tREGION\tID")
+	if err != nil {
+		return fmt.Errorf("cannot write status: %w", err)
+	}
+	for _, flavor := range releaseStatus.Flavors {
+		for _, target := range flavor.Targets {
+			for _, img := range target.Images {
+				_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", flavor.Cname, target.Target, orDash(img.Cloud), orDash(img.Region), img.ID)
+				if err != nil {
+					return fmt.Errorf("cannot write status: %w", err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func statusCell(flavor glci.FlavorStatus, target string) string {
+	for _, t := range flavor.Targets {
+		if t.Target != target {
+			continue
+		}
+		if !flavor.Manifest {
+			return "manifest missing"
+		}
+		if !t.Published {
+			return "not published"
+		}
+
+		var clouds []string
+		counts := make(map[string]int)
+		for _, img := range t.Images {
+			_, ok := counts[img.Cloud]
+			if !ok {
+				clouds = append(clouds, img.Cloud)
+			}
+			counts[img.Cloud]++
+		}
+		summary := make([]string, 0, len(clouds))
+		for _, cloud := range clouds {
+			summary = append(summary, fmt.Sprintf("%s:%d", orDash(cloud), counts[cloud]))
+		}
+
+		if len(summary) == 0 {
+			return "published"
+		}
+		return "published (" + strings.Join(summary, ", ") + ")"
+	}
+
+	return "-"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+func unlockCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "unlock",
+		Short: "Release the lock of a Garden Linux release held by a run of GLCI which has died",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(unlock),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().StringP("version", "v", "", "release version")
+	c.Flags().StringP("commit", "c", "", "release commit(ish), discovered from the locks if omitted")
+	c.Flags().Bool("force", false, "release the lock even though another run of GLCI might still hold it")
+	c.Flags().StringP("output", "o", "table", "output format (table, yaml or json)")
+
+	return c
+}
+
+func unlock(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	if !cfg.GetBool("force") {
+		return errors.New("releasing a lock can break a run of GLCI which is still active, use --force to release it anyway")
+	}
+
+	_, publishingCfg, _, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	var lease glci.Lease
+	lease, err = glci.Unlock(ctx, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"))
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the GLCI command.
+	}
+
+	return printLease(cfg.GetString("output"), lease)
+}
+
+func printLease(format string, lease glci.Lease) error {
+	return printOutput(format, lease, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "VERSION\tCOMMIT\tOWNER\tHOSTNAME\tPID\tSTARTED\tEXPIRES")
+		if err != nil {
+			return fmt.Errorf("cannot write lease: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", lease.Version, lease.Commit, orDash(lease.Owner), orDash(lease.Hostname),
+			lease.PID, lease.Started.Format(time.RFC3339), lease.Expires.Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("cannot write lease: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+func verifyCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify that the published images of a Garden Linux release still exist on cloud providers",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(verify),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().StringP("version", "v", "", "release version")
+	c.Flags().StringP("commit", "c", "", "release commit(ish), discovered from the manifests if omitted")
+	c.Flags().StringP("output", "o", "table", "output format (table, yaml or json)")
+
+	return c
+}
+
+func verify(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	flavorsCfg, publishingCfg, _, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	var drift []glci.Drift
+	drift, err = glci.Verify(ctx, flavorsCfg, publishingCfg, creds, cfg.GetString("version"), cfg.GetString("commit"))
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the GLCI command.
+	}
+
+	err = printDrift(cfg.GetString("output"), drift)
+	if err != nil {
+		return err
+	}
+
+	if len(drift) > 0 {
+		return fmt.Errorf("found %d discrepancies", len(drift))
+	}
+
+	return nil
+}
+
+func printDrift(format string, drift []glci.Drift) error {
+	return printOutput(format, drift, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "CNAME\tTARGET\tCLOUD\tREGION\tID\tPROBLEM\tDETAIL")
+		if err != nil {
+			return fmt.Errorf("cannot write drift: %w", err)
+		}
+		for _, d := range drift {
+			_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", d.Cname, d.Target, orDash(d.Cloud), orDash(d.Region), orDash(d.ID),
+				d.Problem, orDash(d.Detail))
+			if err != nil {
+				return fmt.Errorf("cannot write drift: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/gardenlinux/glci/internal/cmd"
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+func watchCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "watch",
+		Short: "Publish new Garden Linux releases as soon as their manifests appear",
+		Args:  cobra.NoArgs,
+		RunE:  cmd.RunFunc(watch),
+	}
+
+	c.Flags().String("credentials-file", "", "path to credentials YAML file")
+	c.Flags().String("credentials-base64", "", "base64 encoded credentials YAML (overrides --credentials-file)")
+	c.Flags().Duration("interval", 5*time.Minute, "delay between two polls of the manifest source")
+	c.Flags().String("constraint", "", "only publish versions satisfying this semver constraint, such as >= 1877")
+	c.Flags().Int("max-attempts", 3, "number of times the publication of a release is attempted before it is given up")
+	c.Flags().Bool("publish-existing", false, "publish releases which are already present when watching for the first time")
+	c.Flags().Bool("once", false, "poll only once instead of until interrupted")
+	c.Flags().Int("parallelism", 1, "maximum number of publications to process concurrently")
+	c.Flags().Bool("keep-going", false, "continue with the remaining publications after a publication has failed")
+	c.Flags().Bool("partial-descriptor", false, "publish a component descriptor of the successful publications if some have failed")
+	c.Flags().Bool("keep-leftovers", false, "keep the resources of failed publications instead of rolling them back")
+
+	return c
+}
+
+func watch(ctx context.Context, cfg *viper.Viper) error {
+	ctx = glci.WithVersion(ctx, version)
+	log.Info(ctx, "GLCI", "version", version)
+
+	flavorsCfg, publishingCfg, aliasesCfg, creds, err := loadConfigAndCredentials(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	//nolint:wrapcheck // Directly wraps the GLCI command.
+	return glci.Watch(ctx, flavorsCfg, publishingCfg, aliasesCfg, creds, glci.WatchOptions{
+		Interval:        cfg.GetDuration("interval"),
+		Constraint:      cfg.GetString("constraint"),
+		MaxAttempts:     cfg.GetInt("max-attempts"),
+		PublishExisting: cfg.GetBool("publish-existing"),
+		Once:            cfg.GetBool("once"),
+		Publish:         options(cfg),
+	})
+}
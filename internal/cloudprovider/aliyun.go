@@ -2,9 +2,11 @@ package cloudprovider
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/alibabacloud-go/ecs-20140526/v7/client"
 	"github.com/aliyun/alibabacloud-oss-go-sdk-v2/oss"
 	"github.com/aliyun/alibabacloud-oss-go-sdk-v2/oss/credentials"
+	ossretry "github.com/aliyun/alibabacloud-oss-go-sdk-v2/oss/retry"
 
 	"github.com/gardenlinux/glci/internal/env"
 	"github.com/gardenlinux/glci/internal/gl"
@@ -91,39 +94,71 @@ func (*aliyun) ImageSuffix() string {
 	return ".qcow2"
 }
 
+func (*aliyun) Platforms() []string {
+	return []string{"ali"}
+}
+
+func (*aliyun) Clouds() []string {
+	return nil
+}
+
 func (p *aliyun) IsPublished(manifest *gl.Manifest) (bool, error) {
 	if !p.isConfigured() {
 		return false, errors.New("config not set")
 	}
 
-	aliyunOutput, err := publishingOutputFromManifest[aliyunPublishingOutput](manifest)
-	if err != nil {
-		return false, err
+	// With a region filter, every selected region must have been published to.
+	published := p.publishedRegions(manifest)
+	for _, region := range p.filterRegions {
+		_, ok := published[region]
+		if !ok {
+			return false, nil
+		}
 	}
 
-	return aliyunOutput.Images != nil && len(*aliyunOutput.Images) != 0, nil
+	return len(published) != 0, nil
 }
 
-func (p *aliyun) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
+func (p *aliyun) PublishedImages(manifest *gl.Manifest) ([]PublishedImage, error) {
 	if !p.isConfigured() {
 		return nil, errors.New("config not set")
 	}
 
-	aliyunOutput, err := publishingOutput[aliyunPublishingOutput](output)
-	if err != nil {
-		return nil, err
+	aliyunOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
+	if len(aliyunOutput.AliyunImages) == 0 {
+		return nil, nil
 	}
-	var ownOutput aliyunPublishingOutput
-	ownOutput, err = publishingOutput[aliyunPublishingOutput](own)
-	if err != nil {
-		return nil, err
+	images := make([]PublishedImage, 0, len(aliyunOutput.AliyunImages))
+	for _, img := range aliyunOutput.AliyunImages {
+		if !p.filter.matchesRegion(img.Region) {
+			continue
+		}
+		images = append(images, PublishedImage{
+			Region: img.Region,
+			ID:     img.ID,
+			Name:   img.Image,
+		})
 	}
 
-	if aliyunOutput.Images != nil && len(*aliyunOutput.Images) != 0 {
-		return nil, errors.New("cannot add publishing output to existing publishing output")
+	return images, nil
+}
+
+func (p *aliyun) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
 
-	return &ownOutput, nil
+	aliyunOutput := publishedImageMetadata(output)
+	ownOutput := publishedImageMetadata(own)
+
+	// A publication restricted to some regions adds to the images already published in the other regions.
+	aliyunOutput.AliyunImages = slices.DeleteFunc(slices.Clone(aliyunOutput.AliyunImages), func(img gl.AliyunImage) bool {
+		return slices.ContainsFunc(ownOutput.AliyunImages, func(ownImg gl.AliyunImage) bool {
+			return ownImg.Region == img.Region
+		})
+	})
+	aliyunOutput.AliyunImages = slices.Concat(aliyunOutput.AliyunImages, ownOutput.AliyunImages)
+	return &aliyunOutput, nil
 }
 
 func (p *aliyun) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutput, error) {
@@ -131,12 +166,17 @@ func (p *aliyun) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingO
 		return nil, errors.New("config not set")
 	}
 
-	_, err := publishingOutput[aliyunPublishingOutput](output)
-	if err != nil {
-		return nil, err
+	aliyunOutput := publishedImageMetadata(output)
+
+	var otherImages []gl.AliyunImage
+	for _, img := range aliyunOutput.AliyunImages {
+		if !p.filter.matchesRegion(img.Region) {
+			otherImages = append(otherImages, img)
+		}
 	}
+	aliyunOutput.AliyunImages = otherImages
 
-	return nil, nil
+	return remainingPublishingOutput(aliyunOutput), nil
 }
 
 func (p *aliyun) Publish(ctx context.Context, cname string, manifest *gl.Manifest, sources map[string]ArtifactSource) (PublishingOutput,
@@ -164,28 +204,27 @@ func (p *aliyun) Publish(ctx context.Context, cname string, manifest *gl.Manifes
 	if p.pubCfg.Regions != nil {
 		regions = slc.Subset(regions, *p.pubCfg.Regions)
 	}
+	regions = p.filter.narrowRegions(ctx, regions, region)
 	if len(regions) == 0 {
 		return nil, errors.New("no available regions")
 	}
-
-	var blob string
-	blob, err = p.uploadBlob(ctx, source, imagePath.S3Key, image)
-	if err != nil {
-		return nil, fmt.Errorf("cannot upload blob for image %s: %w", image, err)
+	published := p.publishedRegions(manifest)
+	regions = unpublishedRegions(ctx, regions, published)
+	if len(regions) == 0 {
+		return &gl.PublishedImageMetadata{}, nil
 	}
 
-	var imageID string
-	imageID, err = p.importImage(ctx, blob, image)
-	if err != nil {
-		return nil, fmt.Errorf("cannot import image %s from blob %s: %w", image, blob, err)
+	imageID, ok := published[region]
+	if ok {
+		log.Info(ctx, "Copying already published image", "imageID", imageID)
+	} else {
+		imageID, err = p.uploadImage(ctx, source, imagePath, image, region)
+		if err != nil {
+			return nil, err
+		}
 	}
 	ctx = log.WithValues(ctx, "imageID", imageID)
 
-	err = p.deleteBlob(ctx, image)
-	if err != nil {
-		return nil, fmt.Errorf("cannot delete blob %s: %w", image, err)
-	}
-
 	var images map[string]string
 	images, err = p.copyImage(ctx, image, imageID, region, regions)
 	if err != nil {
@@ -202,37 +241,194 @@ func (p *aliyun) Publish(ctx context.Context, cname string, manifest *gl.Manifes
 		return nil, fmt.Errorf("cannot make images public: %w", err)
 	}
 
-	outputImages := make([]aliyunPublishedImage, 0, len(images))
+	outputImages := make([]gl.AliyunImage, 0, len(images))
 	for region, imageID = range images {
-		outputImages = append(outputImages, aliyunPublishedImage{
+		outputImages = append(outputImages, gl.AliyunImage{
 			Region: region,
 			ID:     imageID,
 			Image:  image,
 		})
 	}
-	return &aliyunPublishingOutput{
-		Images: &outputImages,
+	return &gl.PublishedImageMetadata{
+		AliyunImages: outputImages,
 	}, nil
 }
 
-func (p *aliyun) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]ArtifactSource) error {
+func (p *aliyun) Verify(ctx context.Context, manifest *gl.Manifest) ([]ImageProblem, error) {
 	if !p.isConfigured() {
-		return errors.New("config not set")
+		return nil, errors.New("config not set")
 	}
 	ctx = log.WithValues(ctx, "target", p.Type())
 
-	pubOut, err := publishingOutputFromManifest[aliyunPublishingOutput](manifest)
+	images, err := p.PublishedImages(manifest)
 	if err != nil {
-		return fmt.Errorf("invalid manifest: %w", err)
+		return nil, fmt.Errorf("invalid manifest: %w", err)
 	}
-	if pubOut.Images == nil {
+
+	var regions []string
+	regions, err = p.listRegions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list regions: %w", err)
+	}
+	if p.pubCfg.Regions != nil {
+		regions = slc.Subset(regions, *p.pubCfg.Regions)
+	}
+
+	var problems []ImageProblem
+	found := make(map[string]struct{}, len(regions))
+	for _, img := range images {
+		found[img.Region] = struct{}{}
+
+		var problem *ImageProblem
+		problem, err = p.verifyImage(log.WithValues(ctx, "region", img.Region, "imageID", img.ID), img)
+		if err != nil {
+			return nil, fmt.Errorf("cannot verify image %s in region %s: %w", img.ID, img.Region, err)
+		}
+		if problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+	for _, region := range regions {
+		_, ok := found[region]
+		if !ok {
+			problems = append(problems, ImageProblem{
+				Image: PublishedImage{
+					Region: region,
+				},
+				Problem: ProblemMissingRegion,
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+func (p *aliyun) SetFilter(ctx context.Context, filter Filter) (bool, error) {
+	if !p.isConfigured() {
+		return false, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	if len(filter.Clouds) > 0 {
+		log.Info(ctx, "Excluding target, it has no clouds to select")
+		return false, nil
+	}
+
+	if len(filter.Regions) > 0 {
+		regions, err := p.listRegions(ctx)
+		if err != nil {
+			return false, fmt.Errorf("cannot list regions: %w", err)
+		}
+		if p.pubCfg.Regions != nil {
+			regions = slc.Subset(regions, *p.pubCfg.Regions)
+		}
+		p.filterRegions = filter.selectRegions(regions)
+		if len(p.filterRegions) == 0 {
+			log.Info(ctx, "Excluding target, no region selected")
+			return false, nil
+		}
+	}
+	p.filter = filter
+
+	return true, nil
+}
+
+func (p *aliyun) Orphans(ctx context.Context, published []PublishedImage) ([]Resource, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	referenced := make(map[string]struct{}, len(published))
+	for _, img := range published {
+		referenced[img.ID] = struct{}{}
+	}
+
+	regions, err := p.listRegions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list regions: %w", err)
+	}
+	if p.pubCfg.Regions != nil {
+		regions = slc.Subset(regions, *p.pubCfg.Regions)
+	}
+
+	var orphans []Resource
+	for _, region := range regions {
+		var regionOrphans []Resource
+		regionOrphans, err = p.listOrphanedImages(log.WithValues(ctx, "region", region), region, referenced)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list orphans in region %s: %w", region, err)
+		}
+		orphans = append(orphans, regionOrphans...)
+	}
+
+	// Blobs are only needed while an image is being imported, any remaining blob is a leftover.
+	log.Debug(ctx, "Listing blobs", "bucket", p.pubCfg.Bucket)
+	paginator := p.ossClient.NewListObjectsV2Paginator(&oss.ListObjectsV2Request{
+		Bucket: &p.pubCfg.Bucket,
+		Prefix: ptr.P("gardenlinux-"),
+	})
+	for paginator.HasNext() {
+		var page *oss.ListObjectsV2Result
+		page, err = paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list objects in bucket %s: %w", p.pubCfg.Bucket, err)
+		}
+
+		for _, obj := range page.Contents {
+			if obj.Key == nil {
+				return nil, fmt.Errorf("cannot list objects in bucket %s: missing key", p.pubCfg.Bucket)
+			}
+			if !strings.HasSuffix(*obj.Key, p.ImageSuffix()) {
+				continue
+			}
+			orphans = append(orphans, Resource{
+				Kind:    ResourceKindBlob,
+				Region:  p.creds[p.pubCfg.Config].Region,
+				ID:      *obj.Key,
+				Name:    *obj.Key,
+				Created: ptr.V(obj.LastModified),
+			})
+		}
+	}
+
+	return orphans, nil
+}
+
+func (p *aliyun) DeleteOrphan(ctx context.Context, resource Resource) error {
+	if !p.isConfigured() {
+		return errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type(), "region", resource.Region)
+
+	switch resource.Kind {
+	case ResourceKindImage:
+		return p.deleteImage(log.WithValues(ctx, "image", resource.ID), resource.ID, resource.Region)
+	case ResourceKindBlob:
+		return p.deleteBlob(ctx, strings.TrimSuffix(resource.ID, p.ImageSuffix()))
+	default:
+		return fmt.Errorf("unsupported resource kind %s", resource.Kind)
+	}
+}
+
+func (p *aliyun) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]ArtifactSource) error {
+	if !p.isConfigured() {
+		return errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	pubOut := publishedImageMetadata(manifest.PublishedImageMetadata)
+	if len(pubOut.AliyunImages) == 0 {
 		return errors.New("invalid manifest: missing published images")
 	}
 
-	for _, img := range *pubOut.Images {
+	for _, img := range pubOut.AliyunImages {
+		if !p.filter.matchesRegion(img.Region) {
+			continue
+		}
 		lctx := log.WithValues(ctx, "image", img.ID, "fromRegion", img.Region)
 
-		err = p.deleteImage(lctx, img.ID, img.Region)
+		err := p.deleteImage(lctx, img.ID, img.Region)
 		if err != nil {
 			return fmt.Errorf("cannot delete image %s in region %s: %w", img.ID, img.Region, err)
 		}
@@ -244,6 +440,8 @@ func (p *aliyun) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string
 type aliyun struct {
 	creds           map[string]aliyunCredentials
 	pubCfg          aliyunPublishingConfig
+	filter          Filter
+	filterRegions   []string
 	ossClient       *oss.Client
 	ecsClients      map[string]*client.Client
 	ecsClientsMutex sync.RWMutex
@@ -262,17 +460,10 @@ type aliyunPublishingConfig struct {
 	Regions *[]string `mapstructure:"regions,omitempty"`
 }
 
-type aliyunPublishingOutput struct {
-	Images *[]aliyunPublishedImage `yaml:"published_alicloud_images,omitempty"`
-}
-
-type aliyunPublishedImage struct {
-	Region string `yaml:"region_id"`
-	ID     string `yaml:"image_id"`
-	Image  string `yaml:"image_name"`
-}
-
 func (p *aliyun) isConfigured() bool {
+	p.ecsClientsMutex.RLock()
+	defer p.ecsClientsMutex.RUnlock()
+
 	return p.ossClient != nil && len(p.ecsClients) != 0
 }
 
@@ -280,11 +471,55 @@ func (*aliyun) imageName(cname, version, committish string) string {
 	return fmt.Sprintf("gardenlinux-%s-%s-%.8s", cname, version, committish)
 }
 
-func (p *aliyun) uploadBlob(ctx context.Context, source ArtifactSource, key, image string) (string, error) {
+// uploadImage uploads an image into a blob and imports it from there into a region.
+func (p *aliyun) uploadImage(ctx context.Context, source ArtifactSource, imagePath gl.S3ReleaseFile, image, region string,
+) (string, error) {
+	blob, err := journaled(ctx, "blob", func() (string, error) {
+		return p.uploadBlob(ctx, source, imagePath, image)
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot upload blob for image %s: %w", image, err)
+	}
+	track(ctx, "blob", "blob "+blob, func(ctx context.Context) error {
+		return p.deleteBlob(ctx, image)
+	})
+
+	var imageID string
+	imageID, err = journaled(ctx, "image", func() (string, error) {
+		return p.importImage(ctx, blob, image)
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot import image %s from blob %s: %w", image, blob, err)
+	}
+	p.trackImage(log.WithValues(ctx, "imageID", imageID), "image", imageID, region)
+
+	_, err = journaled(ctx, "blob_deleted", func() (string, error) {
+		return blob, p.deleteBlob(ctx, image)
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot delete blob %s: %w", image, err)
+	}
+	untrack(ctx, "blob")
+
+	return imageID, nil
+}
+
+// publishedRegions returns the IDs of the images which are recorded in a manifest by region.
+func (*aliyun) publishedRegions(manifest *gl.Manifest) map[string]string {
+	aliyunOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
+	published := make(map[string]string, len(aliyunOutput.AliyunImages))
+	for _, img := range aliyunOutput.AliyunImages {
+		published[img.Region] = img.ID
+	}
+
+	return published
+}
+
+func (p *aliyun) uploadBlob(ctx context.Context, source ArtifactSource, file gl.S3ReleaseFile, image string) (string, error) {
 	ossKey := image + p.ImageSuffix()
-	ctx = log.WithValues(ctx, "bucket", p.pubCfg.Bucket, "key", key, "ossKey", ossKey)
+	ctx = log.WithValues(ctx, "bucket", p.pubCfg.Bucket, "key", file.S3Key, "ossKey", ossKey)
 
-	obj, err := source.GetObject(ctx, key)
+	obj, err := getReleaseFile(ctx, source, file)
 	if err != nil {
 		return "", fmt.Errorf("cannot get blob: %w", err)
 	}
@@ -322,7 +557,9 @@ func (p *aliyun) listRegions(ctx context.Context) ([]string, error) {
 		return nil, fmt.Errorf("cannot describe regions: %w", err)
 	}
 	var r *client.DescribeRegionsResponse
-	r, err = c.DescribeRegions(&client.DescribeRegionsRequest{})
+	r, err = retried(ctx, "describe regions", func(context.Context) (*client.DescribeRegionsResponse, error) {
+		return c.DescribeRegions(&client.DescribeRegionsRequest{})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot describe regions: %w", err)
 	}
@@ -360,21 +597,25 @@ func (p *aliyun) importImage(ctx context.Context, blob, image string) (string, e
 	if err != nil {
 		return "", fmt.Errorf("cannot import image: %w", err)
 	}
+	token := rand.Text()
 	var r *client.ImportImageResponse
-	r, err = c.ImportImage(&client.ImportImageRequest{
-		DiskDeviceMapping: []*client.ImportImageRequestDiskDeviceMapping{
-			{
-				DiskImageSize: ptr.P(int32(20)),
-				Format:        ptr.P("qcow2"),
-				OSSBucket:     &p.pubCfg.Bucket,
-				OSSObject:     &blob,
+	r, err = retried(ctx, "import image", func(context.Context) (*client.ImportImageResponse, error) {
+		return c.ImportImage(&client.ImportImageRequest{
+			ClientToken: &token,
+			DiskDeviceMapping: []*client.ImportImageRequestDiskDeviceMapping{
+				{
+					DiskImageSize: ptr.P(int32(20)),
+					Format:        ptr.P("qcow2"),
+					OSSBucket:     &p.pubCfg.Bucket,
+					OSSObject:     &blob,
+				},
 			},
-		},
-		Features: &client.ImportImageRequestFeatures{
-			NvmeSupport: ptr.P("supported"),
-		},
-		ImageName: &image,
-		RegionId:  &region,
+			Features: &client.ImportImageRequestFeatures{
+				NvmeSupport: ptr.P("supported"),
+			},
+			ImageName: &image,
+			RegionId:  &region,
+		})
 	})
 	if err != nil {
 		return "", fmt.Errorf("cannot import image: %w", err)
@@ -387,7 +628,7 @@ func (p *aliyun) importImage(ctx context.Context, blob, image string) (string, e
 	}
 	imageID := *r.Body.ImageId
 
-	err = p.waitForImage(ctx, region, imageID)
+	err = p.waitForImage(ctx, PollStepImport, region, imageID)
 	if err != nil {
 		return "", err
 	}
@@ -401,9 +642,11 @@ func (p *aliyun) deleteBlob(ctx context.Context, image string) error {
 	ctx = log.WithValues(ctx, "bucket", p.pubCfg.Bucket, "ossKey", ossKey)
 
 	log.Info(ctx, "Deleting blob")
-	_, err := p.ossClient.DeleteObject(ctx, &oss.DeleteObjectRequest{
-		Bucket: &p.pubCfg.Bucket,
-		Key:    &ossKey,
+	_, err := retried(ctx, "delete object", func(ctx context.Context) (*oss.DeleteObjectResult, error) {
+		return p.ossClient.DeleteObject(ctx, &oss.DeleteObjectRequest{
+			Bucket: &p.pubCfg.Bucket,
+			Key:    &ossKey,
+		}, withoutOSSRetries)
 	})
 	if err != nil {
 		return fmt.Errorf("cannot delete object %s in bucket %s: %w", p.pubCfg.Bucket, ossKey, err)
@@ -412,57 +655,59 @@ func (p *aliyun) deleteBlob(ctx context.Context, image string) error {
 	return nil
 }
 
-func (p *aliyun) waitForImage(ctx context.Context, region, imageID string) error {
-	var status string
+func (p *aliyun) waitForImage(ctx context.Context, step PollStep, region, imageID string) error {
 	c, err := p.ecsClient(region)
 	if err != nil {
 		return err
 	}
 
-	for status != "Available" {
-		log.Debug(ctx, "Waiting for image", "toRegion", region, "toImageID", imageID)
-		err = ctx.Err()
+	return poll(ctx, step, "image "+imageID+" in region "+region, func(ctx context.Context) (bool, string, error) {
+		err := ctx.Err()
 		if err != nil {
-			return fmt.Errorf("cannot get status of image %s in region %s: %w", imageID, region, err)
+			return false, "", fmt.Errorf("cannot get status of image %s in region %s: %w", imageID, region, err)
 		}
 		var r *client.DescribeImagesResponse
-		r, err = c.DescribeImages(&client.DescribeImagesRequest{
-			ImageId:  &imageID,
-			RegionId: &region,
+		r, err = retried(ctx, "describe images", func(context.Context) (*client.DescribeImagesResponse, error) {
+			return c.DescribeImages(&client.DescribeImagesRequest{
+				ImageId:  &imageID,
+				RegionId: &region,
+			})
 		})
 		if err != nil {
-			return fmt.Errorf("cannot get status of image %s in region %s: %w", imageID, region, err)
+			return false, "", fmt.Errorf("cannot get status of image %s in region %s: %w", imageID, region, err)
 		}
 		if r.Body == nil {
-			return fmt.Errorf("cannot get status of image %s in region %s: missing body", imageID, region)
+			return false, "", fmt.Errorf("cannot get status of image %s in region %s: missing body", imageID, region)
 		}
 		if r.Body.Images == nil || len(r.Body.Images.Image) > 1 {
-			return fmt.Errorf("cannot get status of image %s in region %s: missing images", imageID, region)
+			return false, "", fmt.Errorf("cannot get status of image %s in region %s: missing images", imageID, region)
 		}
-		if len(r.Body.Images.Image) == 1 {
-			if r.Body.Images.Image[0] == nil {
-				return fmt.Errorf("cannot get status of image %s in region %s: missing image", imageID, region)
-			}
-			if r.Body.Images.Image[0].Status == nil {
-				return fmt.Errorf("cannot get status of image %s in region %s: missing status", imageID, region)
-			}
-			status = *r.Body.Images.Image[0].Status
+		// Images are only listed once they are available.
+		if len(r.Body.Images.Image) == 0 {
+			return false, "", nil
 		}
-
+		if r.Body.Images.Image[0] == nil {
+			return false, "", fmt.Errorf("cannot get status of image %s in region %s: missing image", imageID, region)
+		}
+		if r.Body.Images.Image[0].Status == nil {
+			return false, "", fmt.Errorf("cannot get status of image %s in region %s: missing status", imageID, region)
+		}
+		status := *r.Body.Images.Image[0].Status
 		if status != "Available" {
-			if status != "" {
-				return fmt.Errorf("image %s in region %s has status %s", imageID, region, status)
-			}
-
-			time.Sleep(time.Second * 7)
+			return false, "", fmt.Errorf("image %s in region %s has status %s", imageID, region, status)
 		}
-	}
 
-	return nil
+		return true, status, nil
+	})
 }
 
 func (p *aliyun) ecsClient(region string) (*client.Client, error) {
-	mainClient := p.ecsClients[p.creds[p.pubCfg.Config].Region]
+	var mainClient *client.Client
+	func() {
+		p.ecsClientsMutex.RLock()
+		defer p.ecsClientsMutex.RUnlock()
+		mainClient = p.ecsClients[p.creds[p.pubCfg.Config].Region]
+	}()
 	if region == "" || region == *mainClient.RegionId {
 		return mainClient, nil
 	}
@@ -506,32 +751,44 @@ func (p *aliyun) copyImage(ctx context.Context, image, imageID, fromRegion strin
 			continue
 		}
 
-		log.Info(ctx, "Copying image", "toRegion", region)
-		c, err := p.ecsClient("")
-		if err != nil {
-			return nil, err
-		}
-		err = ctx.Err()
-		if err != nil {
-			return nil, fmt.Errorf("cannot copy image %s to region %s: %w", imageID, region, err)
-		}
-		var r *client.CopyImageResponse
-		r, err = c.CopyImage(&client.CopyImageRequest{
-			DestinationImageName: &image,
-			DestinationRegionId:  &region,
-			ImageId:              &imageID,
-			RegionId:             &fromRegion,
+		copyID, err := journaled(ctx, "copy/"+region, func() (string, error) {
+			log.Info(ctx, "Copying image", "toRegion", region)
+			c, err := p.ecsClient("")
+			if err != nil {
+				return "", err
+			}
+			err = ctx.Err()
+			if err != nil {
+				return "", fmt.Errorf("cannot copy image %s to region %s: %w", imageID, region, err)
+			}
+			token := rand.Text()
+			var r *client.CopyImageResponse
+			r, err = retried(ctx, "copy image", func(context.Context) (*client.CopyImageResponse, error) {
+				return c.CopyImage(&client.CopyImageRequest{
+					ClientToken:          &token,
+					DestinationImageName: &image,
+					DestinationRegionId:  &region,
+					ImageId:              &imageID,
+					RegionId:             &fromRegion,
+				})
+			})
+			if err != nil {
+				return "", fmt.Errorf("cannot copy image %s to region %s: %w", imageID, region, err)
+			}
+			if r.Body == nil {
+				return "", fmt.Errorf("cannot copy image %s to region %s: missing body", imageID, region)
+			}
+			if r.Body.ImageId == nil {
+				return "", fmt.Errorf("cannot copy image %s to region %s: missing image ID", imageID, region)
+			}
+
+			return *r.Body.ImageId, nil
 		})
 		if err != nil {
-			return images, fmt.Errorf("cannot copy image %s to region %s: %w", imageID, region, err)
-		}
-		if r.Body == nil {
-			return nil, fmt.Errorf("cannot copy image %s to region %s: missing body", imageID, region)
+			return images, err
 		}
-		if r.Body.ImageId == nil {
-			return nil, fmt.Errorf("cannot copy image %s to region %s: missing image ID", imageID, region)
-		}
-		images[region] = *r.Body.ImageId
+		p.trackImage(ctx, "copy/"+region, copyID, region)
+		images[region] = copyID
 	}
 
 	return images, nil
@@ -539,7 +796,7 @@ func (p *aliyun) copyImage(ctx context.Context, image, imageID, fromRegion strin
 
 func (p *aliyun) waitForImages(ctx context.Context, images map[string]string) error {
 	for region, imageID := range images {
-		err := p.waitForImage(ctx, region, imageID)
+		err := p.waitForImage(ctx, PollStepCopy, region, imageID)
 		if err != nil {
 			return err
 		}
@@ -560,10 +817,12 @@ func (p *aliyun) makePublic(ctx context.Context, images map[string]string) error
 		if err != nil {
 			return fmt.Errorf("cannot modify share permission of image %s in region %s: %w", imageID, region, err)
 		}
-		_, err = c.ModifyImageSharePermission(&client.ModifyImageSharePermissionRequest{
-			ImageId:  &imageID,
-			IsPublic: ptr.P(true),
-			RegionId: &region,
+		_, err = retried(ctx, "modify image share permission", func(context.Context) (*client.ModifyImageSharePermissionResponse, error) {
+			return c.ModifyImageSharePermission(&client.ModifyImageSharePermissionRequest{
+				ImageId:  &imageID,
+				IsPublic: ptr.P(true),
+				RegionId: &region,
+			})
 		})
 		if err != nil {
 			return fmt.Errorf("cannot modify share permission of image %s in region %s: %w", imageID, region, err)
@@ -573,6 +832,131 @@ func (p *aliyun) makePublic(ctx context.Context, images map[string]string) error
 	return nil
 }
 
+func (p *aliyun) verifyImage(ctx context.Context, img PublishedImage) (*ImageProblem, error) {
+	c, err := p.ecsClient(img.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug(ctx, "Verifying image")
+	err = ctx.Err()
+	if err != nil {
+		return nil, fmt.Errorf("cannot describe image: %w", err)
+	}
+	var r *client.DescribeImagesResponse
+	r, err = retried(ctx, "describe images", func(context.Context) (*client.DescribeImagesResponse, error) {
+		return c.DescribeImages(&client.DescribeImagesRequest{
+			ImageId:  &img.ID,
+			RegionId: &img.Region,
+			Status:   ptr.P("Creating,Waiting,Available,UnAvailable,CreateFailed,Deprecated"),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot describe image: %w", err)
+	}
+	if r.Body == nil || r.Body.Images == nil || len(r.Body.Images.Image) > 1 {
+		return nil, errors.New("cannot describe image: missing images")
+	}
+	if len(r.Body.Images.Image) == 0 {
+		return &ImageProblem{
+			Image:   img,
+			Problem: ProblemDeleted,
+		}, nil
+	}
+	image := r.Body.Images.Image[0]
+	if image == nil || image.Status == nil {
+		return nil, errors.New("cannot describe image: missing status")
+	}
+	if *image.Status != "Available" {
+		return &ImageProblem{
+			Image:   img,
+			Problem: ProblemNotAvailable,
+			Detail:  "status " + *image.Status,
+		}, nil
+	}
+	if image.IsPublic == nil || !*image.IsPublic {
+		return &ImageProblem{
+			Image:   img,
+			Problem: ProblemPrivate,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (p *aliyun) listOrphanedImages(ctx context.Context, region string, referenced map[string]struct{}) ([]Resource, error) {
+	c, err := p.ecsClient(region)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug(ctx, "Listing images")
+	var orphans []Resource
+	var listed int32
+	for page := int32(1); ; page++ {
+		err = ctx.Err()
+		if err != nil {
+			return nil, fmt.Errorf("cannot describe images: %w", err)
+		}
+		var r *client.DescribeImagesResponse
+		r, err = retried(ctx, "describe images", func(context.Context) (*client.DescribeImagesResponse, error) {
+			return c.DescribeImages(&client.DescribeImagesRequest{
+				ImageName:       ptr.P("gardenlinux-"),
+				ImageOwnerAlias: ptr.P("self"),
+				PageNumber:      &page,
+				PageSize:        ptr.P(int32(100)),
+				RegionId:        &region,
+				Status:          ptr.P("Creating,Waiting,Available,UnAvailable,CreateFailed,Deprecated"),
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot describe images: %w", err)
+		}
+		if r.Body == nil || r.Body.Images == nil || r.Body.TotalCount == nil {
+			return nil, errors.New("cannot describe images: missing images")
+		}
+
+		for _, image := range r.Body.Images.Image {
+			if image == nil || image.ImageId == nil || image.ImageName == nil {
+				return nil, errors.New("cannot describe images: missing image")
+			}
+			listed++
+			// The image name filter matches fuzzily.
+			if !strings.HasPrefix(*image.ImageName, "gardenlinux-") {
+				continue
+			}
+			_, ok := referenced[*image.ImageId]
+			if ok {
+				continue
+			}
+			var created time.Time
+			if image.CreationTime != nil {
+				created, err = time.Parse(time.RFC3339, *image.CreationTime)
+				if err != nil {
+					return nil, fmt.Errorf("invalid creation time of image %s: %w", *image.ImageId, err)
+				}
+			}
+			orphans = append(orphans, Resource{
+				Kind:    ResourceKindImage,
+				Region:  region,
+				ID:      *image.ImageId,
+				Name:    *image.ImageName,
+				Created: created,
+			})
+		}
+
+		if len(r.Body.Images.Image) == 0 || listed >= *r.Body.TotalCount {
+			return orphans, nil
+		}
+	}
+}
+
+func (p *aliyun) trackImage(ctx context.Context, step, imageID, region string) {
+	track(ctx, step, "image "+imageID+" in region "+region, func(ctx context.Context) error {
+		return p.deleteImage(ctx, imageID, region)
+	})
+}
+
 func (p *aliyun) deleteImage(ctx context.Context, imageID, region string) error {
 	c, err := p.ecsClient(region)
 	if err != nil {
@@ -585,9 +969,11 @@ func (p *aliyun) deleteImage(ctx context.Context, imageID, region string) error
 		return fmt.Errorf("cannot describe image: %w", err)
 	}
 	var r *client.DescribeImagesResponse
-	r, err = c.DescribeImages(&client.DescribeImagesRequest{
-		ImageId:  &imageID,
-		RegionId: &region,
+	r, err = retried(ctx, "describe images", func(context.Context) (*client.DescribeImagesResponse, error) {
+		return c.DescribeImages(&client.DescribeImagesRequest{
+			ImageId:  &imageID,
+			RegionId: &region,
+		})
 	})
 	if err != nil {
 		return fmt.Errorf("cannot describe image: %w", err)
@@ -612,10 +998,12 @@ func (p *aliyun) deleteImage(ctx context.Context, imageID, region string) error
 		if err != nil {
 			return fmt.Errorf("cannot modify share permission of image in region %s: %w", region, err)
 		}
-		_, err = c.ModifyImageSharePermission(&client.ModifyImageSharePermissionRequest{
-			ImageId:  &imageID,
-			IsPublic: ptr.P(false),
-			RegionId: &region,
+		_, err = retried(ctx, "modify image share permission", func(context.Context) (*client.ModifyImageSharePermissionResponse, error) {
+			return c.ModifyImageSharePermission(&client.ModifyImageSharePermissionRequest{
+				ImageId:  &imageID,
+				IsPublic: ptr.P(false),
+				RegionId: &region,
+			})
 		})
 		if err != nil {
 			return fmt.Errorf("cannot modify share permission of image in region %s: %w", region, err)
@@ -627,9 +1015,11 @@ func (p *aliyun) deleteImage(ctx context.Context, imageID, region string) error
 	if err != nil {
 		return fmt.Errorf("cannot delete image %s in region %s: %w", imageID, region, err)
 	}
-	_, err = c.DeleteImage(&client.DeleteImageRequest{
-		ImageId:  &imageID,
-		RegionId: &region,
+	_, err = retried(ctx, "delete image", func(context.Context) (*client.DeleteImageResponse, error) {
+		return c.DeleteImage(&client.DeleteImageRequest{
+			ImageId:  &imageID,
+			RegionId: &region,
+		})
 	})
 	if err != nil {
 		return fmt.Errorf("cannot delete image %s in region %s: %w", imageID, region, err)
@@ -637,3 +1027,9 @@ func (p *aliyun) deleteImage(ctx context.Context, imageID, region string) error
 
 	return nil
 }
+
+// withoutOSSRetries disables the retries of the SDK for an OSS call which is retried by retried. ECS calls are not retried by the SDK
+// unless retry options are configured.
+func withoutOSSRetries(o *oss.Options) {
+	o.Retryer = ossretry.NopRetryer{}
+}
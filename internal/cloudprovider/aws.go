@@ -2,10 +2,13 @@ package cloudprovider
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"slices"
+	"strings"
 	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
@@ -15,6 +18,8 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/logging"
 
 	"github.com/gardenlinux/glci/internal/env"
@@ -81,6 +86,7 @@ func (p *aws) SetTargetConfig(ctx context.Context, cfg map[string]any, sources m
 	}
 
 	p.tgtEC2Clients = make(map[string]*ec2.Client, len(p.pubCfg.Targets))
+	p.tgtSTSClients = make(map[string]*sts.Client, len(p.pubCfg.Targets))
 	for t, target := range p.pubCfg.Targets {
 		_, ok := sources[target.Source]
 		if !ok {
@@ -112,13 +118,18 @@ func (p *aws) SetTargetConfig(ctx context.Context, cfg map[string]any, sources m
 
 		p.pubCfg.Targets[t] = target
 
+		// Every EC2 and STS call is retried by retried, retries of the SDK would multiply its attempts.
 		var awsCfg awssdk.Config
 		awsCfg, err = config.LoadDefaultConfig(ctx, config.WithRegion(creds.Region),
-			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, "")))
+			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, "")),
+			config.WithRetryer(func() awssdk.Retryer {
+				return awssdk.NopRetryer{}
+			}))
 		if err != nil {
 			return fmt.Errorf("cannot load default AWS config: %w", err)
 		}
 		p.tgtEC2Clients[target.Config] = ec2.NewFromConfig(awsCfg)
+		p.tgtSTSClients[target.Config] = sts.NewFromConfig(awsCfg)
 	}
 
 	return nil
@@ -143,9 +154,11 @@ func (p *aws) GetObjectSize(ctx context.Context, key string) (int64, error) {
 	ctx = log.WithValues(ctx, "source", p.Type())
 
 	log.Debug(ctx, "Heading object", "bucket", p.srcCfg.Bucket, "key", key)
-	r, err := p.srcS3Client.HeadObject(ctx, &s3.HeadObjectInput{
-		Bucket: &p.srcCfg.Bucket,
-		Key:    &key,
+	r, err := retried(ctx, "head object", func(ctx context.Context) (*s3.HeadObjectOutput, error) {
+		return p.srcS3Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: &p.srcCfg.Bucket,
+			Key:    &key,
+		}, withoutSDKRetries)
 	})
 	if err != nil {
 		var noSuchKey *s3types.NoSuchKey
@@ -210,147 +223,234 @@ func (p *aws) PutObject(ctx context.Context, key string, object io.Reader) error
 	return nil
 }
 
-func (*aws) ImageSuffix() string {
-	return ".raw"
+func (p *aws) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if p.srcS3Client == nil {
+		return nil, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "source", p.Type())
+
+	log.Debug(ctx, "Listing objects", "bucket", p.srcCfg.Bucket, "prefix", prefix)
+	var objects []ObjectInfo
+	paginator := s3.NewListObjectsV2Paginator(p.srcS3Client, &s3.ListObjectsV2Input{
+		Bucket: &p.srcCfg.Bucket,
+		Prefix: &prefix,
+	})
+	for paginator.HasMorePages() {
+		// The paginator only advances once a page has been retrieved successfully, so a page can be retried.
+		r, err := retried(ctx, "list objects", func(ctx context.Context) (*s3.ListObjectsV2Output, error) {
+			return paginator.NextPage(ctx, withoutSDKRetries)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot list objects with prefix %s in bucket %s: %w", prefix, p.srcCfg.Bucket, err)
+		}
+
+		for _, obj := range r.Contents {
+			if obj.Key == nil {
+				return nil, fmt.Errorf("cannot list objects with prefix %s in bucket %s: missing key", prefix, p.srcCfg.Bucket)
+			}
+			objects = append(objects, ObjectInfo{
+				Key:          *obj.Key,
+				Size:         ptr.V(obj.Size),
+				LastModified: ptr.V(obj.LastModified),
+				ETag:         strings.Trim(ptr.V(obj.ETag), `"`),
+			})
+		}
+	}
+
+	return objects, nil
 }
 
-func (p *aws) IsPublished(manifest *gl.Manifest) (bool, error) {
-	if !p.isConfigured() {
-		return false, errors.New("config not set")
+func (p *aws) DeleteObject(ctx context.Context, key string) error {
+	if p.srcS3Client == nil {
+		return errors.New("config not set")
 	}
+	ctx = log.WithValues(ctx, "source", p.Type())
 
-	awsOutput, err := publishingOutputFromManifest[awsPublishingOutput](manifest)
+	log.Debug(ctx, "Deleting object", "bucket", p.srcCfg.Bucket, "key", key)
+	_, err := retried(ctx, "delete object", func(ctx context.Context) (*s3.DeleteObjectOutput, error) {
+		return p.srcS3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: &p.srcCfg.Bucket,
+			Key:    &key,
+		}, withoutSDKRetries)
+	})
 	if err != nil {
-		return false, err
+		return fmt.Errorf("cannot delete object %s from bucket %s: %w", key, p.srcCfg.Bucket, err)
 	}
 
-	if awsOutput.Images == nil {
-		return false, nil
+	return nil
+}
+
+func (p *aws) CopyObject(ctx context.Context, fromKey, toKey string) error {
+	if p.srcS3Client == nil {
+		return errors.New("config not set")
 	}
-	for _, target := range p.pubCfg.Targets {
-		cld := p.cloud(target)
+	ctx = log.WithValues(ctx, "source", p.Type())
 
-		for _, img := range *awsOutput.Images {
-			if img.Cloud == cld {
-				return true, nil
+	log.Debug(ctx, "Copying object", "bucket", p.srcCfg.Bucket, "fromKey", fromKey, "toKey", toKey)
+	_, err := retried(ctx, "copy object", func(ctx context.Context) (*s3.CopyObjectOutput, error) {
+		return p.srcS3Client.CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:     &p.srcCfg.Bucket,
+			CopySource: ptr.P(url.PathEscape(p.srcCfg.Bucket) + "/" + url.PathEscape(fromKey)),
+			Key:        &toKey,
+		}, withoutSDKRetries)
+	})
+	if err != nil {
+		var noSuchKey *s3types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			err = KeyNotFoundError{
+				err: err,
 			}
 		}
+
+		return fmt.Errorf("cannot copy object %s to %s in bucket %s: %w", fromKey, toKey, p.srcCfg.Bucket, err)
 	}
 
-	return false, nil
+	return nil
 }
 
-func (p *aws) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
-	if !p.isConfigured() {
-		return nil, errors.New("config not set")
-	}
+func (*aws) ImageSuffix() string {
+	return ".raw"
+}
 
-	awsOutput, err := publishingOutput[awsPublishingOutput](output)
-	if err != nil {
-		return nil, err
+func (*aws) Platforms() []string {
+	return []string{"aws"}
+}
+
+func (p *aws) Clouds() []string {
+	clouds := make([]string, 0, len(p.pubCfg.Targets))
+	for _, target := range p.pubCfg.Targets {
+		if !slices.Contains(clouds, p.cloud(target)) {
+			clouds = append(clouds, p.cloud(target))
+		}
 	}
-	var ownOutput awsPublishingOutput
-	ownOutput, err = publishingOutput[awsPublishingOutput](own)
-	if err != nil {
-		return nil, err
+
+	return clouds
+}
+
+func (p *aws) IsPublished(manifest *gl.Manifest) (bool, error) {
+	if !p.isConfigured() {
+		return false, errors.New("config not set")
 	}
 
-	if ownOutput.Images == nil {
-		ownOutput.Images = &[]awsPublishedImage{}
+	// Every cloud must have been published to, and with a region filter every selected region as well.
+	for _, target := range p.pubCfg.Targets {
+		cld := p.cloud(target)
+		published := p.publishedRegions(manifest, cld)
+		if len(published) == 0 {
+			return false, nil
+		}
+		for _, region := range p.filterRegions[cld] {
+			_, ok := published[region]
+			if !ok {
+				return false, nil
+			}
+		}
 	}
 
-	if awsOutput.Images == nil {
-		return &ownOutput, nil
+	return len(p.pubCfg.Targets) > 0, nil
+}
+
+func (p *aws) PublishedImages(manifest *gl.Manifest) ([]PublishedImage, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
+
+	awsOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
+	var images []PublishedImage
 	for _, target := range p.pubCfg.Targets {
 		cld := p.cloud(target)
 
-		for _, img := range *awsOutput.Images {
-			if img.Cloud == cld {
-				return nil, errors.New("cannot add publishing output to existing publishing output")
+		for _, img := range awsOutput.AWSImages {
+			if img.Cloud == cld && p.filter.matchesRegion(img.Region) {
+				images = append(images, PublishedImage{
+					Cloud:  img.Cloud,
+					Region: img.Region,
+					ID:     img.ID,
+					Name:   img.Image,
+				})
 			}
 		}
 	}
 
-	ownOutput.Images = ptr.P(slices.Concat(*awsOutput.Images, *ownOutput.Images))
-	return &ownOutput, nil
+	return images, nil
 }
 
-func (p *aws) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutput, error) {
+func (p *aws) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
 	if !p.isConfigured() {
 		return nil, errors.New("config not set")
 	}
 
-	awsOutput, err := publishingOutput[awsPublishingOutput](output)
-	if err != nil {
-		return nil, err
+	awsOutput := publishedImageMetadata(output)
+	ownOutput := publishedImageMetadata(own)
+
+	// A publication restricted to some regions adds to the images already published in the other regions.
+	awsOutput.AWSImages = slices.DeleteFunc(slices.Clone(awsOutput.AWSImages), func(img gl.AWSImage) bool {
+		return slices.ContainsFunc(ownOutput.AWSImages, func(ownImg gl.AWSImage) bool {
+			return ownImg.Cloud == img.Cloud && ownImg.Region == img.Region
+		})
+	})
+	awsOutput.AWSImages = slices.Concat(awsOutput.AWSImages, ownOutput.AWSImages)
+	return &awsOutput, nil
+}
+
+func (p *aws) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutput, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
 
-	var fout []awsPublishedImage
-	if awsOutput.Images != nil {
-		fout = slices.Clone(*awsOutput.Images)
+	awsOutput := publishedImageMetadata(output)
+	awsOutput.AWSImages = slices.DeleteFunc(slices.Clone(awsOutput.AWSImages), func(img gl.AWSImage) bool {
+		return slices.ContainsFunc(p.pubCfg.Targets, func(target awsTarget) bool {
+			return img.Cloud == p.cloud(target) && p.filter.matchesRegion(img.Region)
+		})
+	})
+	if len(awsOutput.AWSImages) == 0 {
+		awsOutput.AWSImages = nil
+	}
 
-		for _, target := range p.pubCfg.Targets {
-			cld := p.cloud(target)
+	return remainingPublishingOutput(awsOutput), nil
+}
 
-			for i, img := range fout {
-				if img.Cloud == cld {
-					img.Cloud = ""
-					fout[i] = img
-				}
-			}
-		}
+func (p *aws) Publish(ctx context.Context, cname string, manifest *gl.Manifest, sources map[string]ArtifactSource) (PublishingOutput, error,
+) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
 
-	var otherImages []awsPublishedImage
-	for _, img := range fout {
-		if img.Cloud != "" {
-			otherImages = append(otherImages, img)
-		}
+	return p.publish(ctx, cname, manifest, sources, nil, nil)
+}
+
+func (p *aws) Promote(ctx context.Context, cname string, manifest *gl.Manifest, from PublishingTarget, fromManifest *gl.Manifest,
+	sources map[string]ArtifactSource,
+) (PublishingOutput, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
-	if len(otherImages) == 0 {
-		return nil, nil
+
+	fromAWS, ok := from.(*aws)
+	if !ok {
+		return nil, fmt.Errorf("cannot promote from %s", from.Type())
 	}
 
-	return &awsPublishingOutput{
-		Images: &otherImages,
-	}, nil
+	return p.publish(ctx, cname, manifest, sources, fromAWS, fromManifest)
 }
 
-func (p *aws) Publish(ctx context.Context, cname string, manifest *gl.Manifest, sources map[string]ArtifactSource) (PublishingOutput, error,
-) {
+func (p *aws) Verify(ctx context.Context, manifest *gl.Manifest) ([]ImageProblem, error) {
 	if !p.isConfigured() {
 		return nil, errors.New("config not set")
 	}
 	ctx = log.WithValues(ctx, "target", p.Type())
 
-	image := p.imageName(cname, manifest.Version, manifest.BuildCommittish)
-	imagePath, err := manifest.PathBySuffix(p.ImageSuffix())
-	if err != nil {
-		return nil, fmt.Errorf("missing image: %w", err)
-	}
-	var arch ec2types.ArchitectureValues
-	arch, err = p.architecture(manifest.Architecture)
+	images, err := p.PublishedImages(manifest)
 	if err != nil {
-		return nil, fmt.Errorf("invalid manifest %s: %w", cname, err)
+		return nil, fmt.Errorf("invalid manifest: %w", err)
 	}
-	tags := p.prepareTags(manifest)
-	ctx = log.WithValues(ctx, "image", image, "architecture", arch)
 
-	var outputImages []awsPublishedImage
+	var problems []ImageProblem
 	for _, target := range p.pubCfg.Targets {
-		source := sources[target.Source]
 		ec2Client := p.tgtEC2Clients[target.Config]
-		region := p.creds[target.Config].Region
-		lctx := log.WithValues(ctx, "sourceType", source.Type(), "sourceRepo", source.Repository(), "region", region)
-
-		var requireUEFI, secureBoot bool
-		var uefiData *string
-		requireUEFI, secureBoot, uefiData, err = p.prepareSecureBoot(lctx, source, manifest)
-		if err != nil {
-			return nil, fmt.Errorf("cannot prepare secureboot: %w", err)
-		}
-		lctx = log.WithValues(lctx, "requireUEFI", requireUEFI, "secureBoot", secureBoot)
+		cld := p.cloud(target)
+		lctx := log.WithValues(ctx, "cloud", cld)
 
 		var regions []string
 		regions, err = p.listRegions(lctx, ec2Client)
@@ -360,58 +460,155 @@ func (p *aws) Publish(ctx context.Context, cname string, manifest *gl.Manifest,
 		if target.Regions != nil {
 			regions = slc.Subset(regions, *target.Regions)
 		}
-		if len(regions) == 0 {
-			return nil, errors.New("no available regions")
-		}
 
-		var snapshot string
-		snapshot, err = p.importSnapshot(lctx, ec2Client, source, imagePath.S3Key, image)
-		if err != nil {
-			return nil, fmt.Errorf("cannot import snapshot for image %s: %w", image, err)
+		found := make(map[string]struct{}, len(regions))
+		for _, img := range images {
+			if img.Cloud != cld {
+				continue
+			}
+			found[img.Region] = struct{}{}
+
+			var problem *ImageProblem
+			problem, err = p.verifyImage(log.WithValues(lctx, "region", img.Region, "id", img.ID), ec2Client, img)
+			if err != nil {
+				return nil, fmt.Errorf("cannot verify image %s in region %s: %w", img.ID, img.Region, err)
+			}
+			if problem != nil {
+				problems = append(problems, *problem)
+			}
+		}
+		for _, region := range regions {
+			_, ok := found[region]
+			if !ok {
+				problems = append(problems, ImageProblem{
+					Image: PublishedImage{
+						Cloud:  cld,
+						Region: region,
+					},
+					Problem: ProblemMissingRegion,
+				})
+			}
 		}
-		lctx = log.WithValues(lctx, "snapshot", snapshot)
+	}
 
-		err = p.attachTags(lctx, ec2Client, snapshot, tags)
-		if err != nil {
-			return nil, fmt.Errorf("cannot attach tags to snapshot %s: %w", snapshot, err)
+	return problems, nil
+}
+
+func (p *aws) SetFilter(ctx context.Context, filter Filter) (bool, error) {
+	if !p.isConfigured() {
+		return false, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	targets := make([]awsTarget, 0, len(p.pubCfg.Targets))
+	filterRegions := make(map[string][]string, len(p.pubCfg.Targets))
+	for _, target := range p.pubCfg.Targets {
+		cld := p.cloud(target)
+		lctx := log.WithValues(ctx, "cloud", cld)
+
+		if !filter.matchesCloud(cld) {
+			log.Info(lctx, "Excluding cloud")
+			continue
 		}
 
-		var imageID string
-		imageID, err = p.registerImage(lctx, ec2Client, snapshot, image, arch, requireUEFI, uefiData)
-		if err != nil {
-			return nil, fmt.Errorf("cannot register image %s from snapshot %s: %w", image, snapshot, err)
+		if len(filter.Regions) > 0 {
+			regions, err := p.listRegions(lctx, p.tgtEC2Clients[target.Config])
+			if err != nil {
+				return false, fmt.Errorf("cannot list regions: %w", err)
+			}
+			if target.Regions != nil {
+				regions = slc.Subset(regions, *target.Regions)
+			}
+			filterRegions[cld] = filter.selectRegions(regions)
+			if len(filterRegions[cld]) == 0 {
+				log.Info(lctx, "Excluding cloud, no region selected")
+				continue
+			}
 		}
-		lctx = log.WithValues(lctx, "imageID", imageID)
 
-		var images map[string]string
-		images, err = p.copyImage(lctx, ec2Client, image, imageID, region, regions)
+		targets = append(targets, target)
+	}
+	p.pubCfg.Targets = targets
+	p.filter = filter
+	p.filterRegions = filterRegions
+
+	return len(targets) > 0, nil
+}
+
+func (p *aws) Orphans(ctx context.Context, published []PublishedImage) ([]Resource, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	referenced := make(map[string]struct{}, len(published))
+	for _, img := range published {
+		referenced[img.ID] = struct{}{}
+	}
+
+	var orphans []Resource
+	seen := make(map[string]struct{})
+	for _, target := range p.pubCfg.Targets {
+		ec2Client := p.tgtEC2Clients[target.Config]
+		cld := p.cloud(target)
+		lctx := log.WithValues(ctx, "cloud", cld)
+
+		regions, err := p.listRegions(lctx, ec2Client)
 		if err != nil {
-			return nil, fmt.Errorf("cannot copy image %s: %w", image, err)
+			return nil, fmt.Errorf("cannot list regions: %w", err)
+		}
+		if target.Regions != nil {
+			regions = slc.Subset(regions, *target.Regions)
 		}
 
-		err = p.waitForImages(lctx, ec2Client, images)
-		if err != nil {
-			return nil, fmt.Errorf("cannot finalize images: %w", err)
+		for _, region := range regions {
+			// Snapshots are only imported into the home region, copies of images own their snapshots.
+			withSnapshots := region == p.creds[target.Config].Region
+
+			var regionOrphans []Resource
+			regionOrphans, err = p.listOrphans(log.WithValues(lctx, "region", region), ec2Client, cld, region, withSnapshots, referenced)
+			if err != nil {
+				return nil, fmt.Errorf("cannot list orphans in region %s: %w", region, err)
+			}
+			for _, orphan := range regionOrphans {
+				_, ok := seen[orphan.ID]
+				if !ok {
+					seen[orphan.ID] = struct{}{}
+					orphans = append(orphans, orphan)
+				}
+			}
 		}
+	}
 
-		err = p.makePublic(lctx, ec2Client, images)
-		if err != nil {
-			return nil, fmt.Errorf("cannot make images public: %w", err)
+	return orphans, nil
+}
+
+func (p *aws) DeleteOrphan(ctx context.Context, resource Resource) error {
+	if !p.isConfigured() {
+		return errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type(), "cloud", resource.Cloud, "region", resource.Region, "id", resource.ID)
+
+	for _, target := range p.pubCfg.Targets {
+		if p.cloud(target) != resource.Cloud {
+			continue
 		}
+		ec2Client := p.tgtEC2Clients[target.Config]
 
-		for region, imageID = range images {
-			outputImages = append(outputImages, awsPublishedImage{
-				Cloud:  p.cloud(target),
-				Region: region,
-				ID:     imageID,
-				Image:  image,
-			})
+		switch resource.Kind {
+		case ResourceKindImage:
+			return p.deregisterImage(ctx, ec2Client, resource.ID, resource.Region)
+		case ResourceKindSnapshot:
+			if resource.Region != p.creds[target.Config].Region {
+				return fmt.Errorf("snapshot %s is not in home region %s", resource.ID, p.creds[target.Config].Region)
+			}
+			return p.deleteSnapshot(ctx, ec2Client, resource.ID)
+		default:
+			return fmt.Errorf("unsupported resource kind %s", resource.Kind)
 		}
 	}
 
-	return &awsPublishingOutput{
-		Images: &outputImages,
-	}, nil
+	return fmt.Errorf("unknown cloud %s", resource.Cloud)
 }
 
 func (p *aws) Remove(ctx context.Context, manifest *gl.Manifest, sources map[string]ArtifactSource) error {
@@ -420,11 +617,8 @@ func (p *aws) Remove(ctx context.Context, manifest *gl.Manifest, sources map[str
 	}
 	ctx = log.WithValues(ctx, "target", p.Type())
 
-	pubOut, err := publishingOutputFromManifest[awsPublishingOutput](manifest)
-	if err != nil {
-		return fmt.Errorf("invalid manifest: %w", err)
-	}
-	if pubOut.Images == nil {
+	pubOut := publishedImageMetadata(manifest.PublishedImageMetadata)
+	if len(pubOut.AWSImages) == 0 {
 		return errors.New("invalid manifest: missing published images")
 	}
 
@@ -439,13 +633,13 @@ func (p *aws) Remove(ctx context.Context, manifest *gl.Manifest, sources map[str
 		ec2Client := p.tgtEC2Clients[target.Config]
 		lctx := log.WithValues(ctx, "cloud", target.Cloud)
 
-		for _, img := range *pubOut.Images {
-			if img.Cloud != p.cloud(target) {
+		for _, img := range pubOut.AWSImages {
+			if img.Cloud != p.cloud(target) || !p.filter.matchesRegion(img.Region) {
 				continue
 			}
 			llctx := log.WithValues(lctx, "region", img.Region, "id", img.ID, "image", img.Image)
 
-			err = p.deregisterImage(llctx, ec2Client, img.ID, img.Region)
+			err := p.deregisterImage(llctx, ec2Client, img.ID, img.Region)
 			if err != nil {
 				return fmt.Errorf("cannot deregister image %s in region %s: %w", img.Image, img.Region, err)
 			}
@@ -459,8 +653,11 @@ type aws struct {
 	creds         map[string]awsCredentials
 	srcCfg        awsSourceConfig
 	pubCfg        awsPublishingConfig
+	filter        Filter
+	filterRegions map[string][]string
 	srcS3Client   *s3.Client
 	tgtEC2Clients map[string]*ec2.Client
+	tgtSTSClients map[string]*sts.Client
 }
 
 type awsCredentials struct {
@@ -493,17 +690,6 @@ type awsImageTags struct {
 	StaticTags                   *map[string]string `mapstructure:"static_tags,omitempty"`
 }
 
-type awsPublishingOutput struct {
-	Images *[]awsPublishedImage `yaml:"published_aws_images,omitempty"`
-}
-
-type awsPublishedImage struct {
-	Cloud  string `yaml:"cloud"`
-	Region string `yaml:"aws_region"`
-	ID     string `yaml:"ami_id"`
-	Image  string `yaml:"image_name"`
-}
-
 func (p *aws) isConfigured() bool {
 	return len(p.tgtEC2Clients) != 0
 }
@@ -580,7 +766,7 @@ func (*aws) prepareSecureBoot(ctx context.Context, source ArtifactSource, manife
 		}
 
 		var efivars []byte
-		efivars, err = getObjectBytes(ctx, source, efivarsFile.S3Key)
+		efivars, err = getObjectBytes(ctx, source, efivarsFile)
 		if err != nil {
 			return false, false, nil, fmt.Errorf("cannot get efivars: %w", err)
 		}
@@ -591,9 +777,234 @@ func (*aws) prepareSecureBoot(ctx context.Context, source ArtifactSource, manife
 	return requireUEFI, secureBoot, uefiData, nil
 }
 
+// publish publishes an image to all targets. If a source is given, the image it has published to the cloud of a target is promoted,
+// targets in clouds the source has not published to import the image instead.
+func (p *aws) publish(ctx context.Context, cname string, manifest *gl.Manifest, sources map[string]ArtifactSource, from *aws,
+	fromManifest *gl.Manifest,
+) (PublishingOutput, error) {
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	image := p.imageName(cname, manifest.Version, manifest.BuildCommittish)
+	arch, err := p.architecture(manifest.Architecture)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", cname, err)
+	}
+	tags := p.prepareTags(manifest)
+	ctx = log.WithValues(ctx, "image", image, "architecture", arch)
+
+	var outputImages []gl.AWSImage
+	for _, target := range p.pubCfg.Targets {
+		cld := p.cloud(target)
+		ec2Client := p.tgtEC2Clients[target.Config]
+		region := p.creds[target.Config].Region
+		lctx := log.WithValues(ctx, "cloud", cld, "region", region)
+
+		var regions []string
+		regions, err = p.publishingRegions(lctx, ec2Client, target, region)
+		if err != nil {
+			return nil, err
+		}
+		published := p.publishedRegions(manifest, cld)
+		regions = unpublishedRegions(lctx, regions, published)
+		if len(regions) == 0 {
+			continue
+		}
+
+		step := target.Config + "/"
+		imageID, ok := published[region]
+		var fromImage gl.AWSImage
+		var fromEC2Client *ec2.Client
+		if !ok && from != nil {
+			fromImage, fromEC2Client, ok = from.promotionSource(fromManifest, cld, region)
+			if !ok {
+				log.Info(lctx, "No source image in cloud, importing image")
+			}
+		}
+		switch {
+		case imageID != "":
+			log.Info(lctx, "Copying already published image", "imageID", imageID)
+		case ok:
+			imageID, err = p.promoteImage(lctx, ec2Client, target, fromImage, fromEC2Client, from, image, tags, step)
+			if err != nil {
+				return nil, fmt.Errorf("cannot promote image %s: %w", image, err)
+			}
+		default:
+			imageID, err = p.importImage(lctx, ec2Client, region, sources[target.Source], manifest, image, arch, tags, step)
+			if err != nil {
+				return nil, err
+			}
+		}
+		lctx = log.WithValues(lctx, "imageID", imageID)
+
+		var images map[string]string
+		images, err = p.copyImage(lctx, ec2Client, image, imageID, region, regions, step+"copy/")
+		if err != nil {
+			return nil, fmt.Errorf("cannot copy image %s: %w", image, err)
+		}
+
+		err = p.waitForImages(lctx, ec2Client, images)
+		if err != nil {
+			return nil, fmt.Errorf("cannot finalize images: %w", err)
+		}
+
+		err = p.makePublic(lctx, ec2Client, images)
+		if err != nil {
+			return nil, fmt.Errorf("cannot make images public: %w", err)
+		}
+
+		for region, imageID = range images {
+			outputImages = append(outputImages, gl.AWSImage{
+				Cloud:  cld,
+				Region: region,
+				ID:     imageID,
+				Image:  image,
+			})
+		}
+	}
+
+	return &gl.PublishedImageMetadata{
+		AWSImages: outputImages,
+	}, nil
+}
+
+// publishingRegions returns the regions an image is copied to for a target.
+func (p *aws) publishingRegions(ctx context.Context, ec2Client *ec2.Client, target awsTarget, region string) ([]string, error) {
+	regions, err := p.listRegions(ctx, ec2Client)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list regions: %w", err)
+	}
+	if target.Regions != nil {
+		regions = slc.Subset(regions, *target.Regions)
+	}
+	regions = p.filter.narrowRegions(ctx, regions, region)
+	if len(regions) == 0 {
+		return nil, errors.New("no available regions")
+	}
+
+	return regions, nil
+}
+
+// promoteImage copies the image published by a source into the region of a target and tags it.
+func (p *aws) promoteImage(ctx context.Context, ec2Client *ec2.Client, target awsTarget, fromImage gl.AWSImage,
+	fromEC2Client *ec2.Client, from *aws, image string, tags []ec2types.Tag, step string,
+) (string, error) {
+	region := p.creds[target.Config].Region
+	ctx = log.WithValues(ctx, "fromImageID", fromImage.ID, "fromRegion", fromImage.Region)
+
+	account, err := p.accountID(ctx, target.Config)
+	if err != nil {
+		return "", err
+	}
+	err = from.shareImage(ctx, fromEC2Client, fromImage.ID, fromImage.Region, account)
+	if err != nil {
+		return "", err
+	}
+
+	var imageID string
+	imageID, err = journaled(ctx, step+"image", func() (string, error) {
+		log.Info(ctx, "Copying source image")
+		return p.copyImageTo(ctx, ec2Client, image, fromImage.ID, fromImage.Region, region)
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot copy source image %s: %w", fromImage.ID, err)
+	}
+	ctx = log.WithValues(ctx, "imageID", imageID)
+	p.trackImage(ctx, step+"image", ec2Client, imageID, region)
+
+	err = p.waitForImages(ctx, ec2Client, map[string]string{
+		region: imageID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot finalize image %s: %w", imageID, err)
+	}
+
+	// Tags of an image owned by another account are not copied.
+	err = p.attachTags(ctx, ec2Client, imageID, tags)
+	if err != nil {
+		return "", fmt.Errorf("cannot attach tags to image %s: %w", imageID, err)
+	}
+
+	return imageID, nil
+}
+
+// publishedRegions returns the IDs of the images of a cloud which are recorded in a manifest by region.
+func (*aws) publishedRegions(manifest *gl.Manifest, cloud string) map[string]string {
+	awsOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
+	published := make(map[string]string, len(awsOutput.AWSImages))
+	for _, img := range awsOutput.AWSImages {
+		if img.Cloud == cloud {
+			published[img.Region] = img.ID
+		}
+	}
+
+	return published
+}
+
+// importImage imports an image into the region of the EC2 client and registers it as an AMI.
+func (p *aws) importImage(ctx context.Context, ec2Client *ec2.Client, region string, source ArtifactSource, manifest *gl.Manifest,
+	image string, arch ec2types.ArchitectureValues, tags []ec2types.Tag, step string,
+) (string, error) {
+	ctx = log.WithValues(ctx, "sourceType", source.Type(), "sourceRepo", source.Repository())
+
+	imagePath, err := manifest.PathBySuffix(p.ImageSuffix())
+	if err != nil {
+		return "", fmt.Errorf("missing image: %w", err)
+	}
+
+	var requireUEFI, secureBoot bool
+	var uefiData *string
+	requireUEFI, secureBoot, uefiData, err = p.prepareSecureBoot(ctx, source, manifest)
+	if err != nil {
+		return "", fmt.Errorf("cannot prepare secureboot: %w", err)
+	}
+	ctx = log.WithValues(ctx, "requireUEFI", requireUEFI, "secureBoot", secureBoot)
+
+	_, err = journaled(ctx, step+"checksum", func() (string, error) {
+		return imagePath.S3Key, verifyReleaseFile(ctx, source, imagePath)
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot verify image %s: %w", image, err)
+	}
+
+	var snapshot string
+	snapshot, err = journaled(ctx, step+"snapshot", func() (string, error) {
+		return p.importSnapshot(ctx, ec2Client, source, imagePath.S3Key, image, step+"import_task")
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot import snapshot for image %s: %w", image, err)
+	}
+	ctx = log.WithValues(ctx, "snapshot", snapshot)
+	track(ctx, step+"snapshot", "snapshot "+snapshot, func(ctx context.Context) error {
+		return p.deleteSnapshot(ctx, ec2Client, snapshot)
+	})
+
+	err = p.attachTags(ctx, ec2Client, snapshot, append(slices.Clone(tags), ec2types.Tag{
+		Key:   ptr.P(awsSnapshotImageTag),
+		Value: &image,
+	}))
+	if err != nil {
+		return "", fmt.Errorf("cannot attach tags to snapshot %s: %w", snapshot, err)
+	}
+
+	var imageID string
+	imageID, err = journaled(ctx, step+"image", func() (string, error) {
+		return p.registerImage(ctx, ec2Client, snapshot, image, arch, requireUEFI, uefiData)
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot register image %s from snapshot %s: %w", image, snapshot, err)
+	}
+	p.trackImage(log.WithValues(ctx, "imageID", imageID), step+"image", ec2Client, imageID, region)
+	// Deregistering the image also deletes its snapshot.
+	untrack(ctx, step+"snapshot")
+
+	return imageID, nil
+}
+
 func (*aws) listRegions(ctx context.Context, ec2Client *ec2.Client) ([]string, error) {
 	log.Debug(ctx, "Listing available regions")
-	r, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	r, err := retried(ctx, "describe regions", func(ctx context.Context) (*ec2.DescribeRegionsOutput, error) {
+		return ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot describe regions: %w", err)
 	}
@@ -609,54 +1020,67 @@ func (*aws) listRegions(ctx context.Context, ec2Client *ec2.Client) ([]string, e
 	return regions, nil
 }
 
-func (*aws) importSnapshot(ctx context.Context, ec2Client *ec2.Client, source ArtifactSource, key, image string) (string, error) {
+func (*aws) importSnapshot(ctx context.Context, ec2Client *ec2.Client, source ArtifactSource, key, image, taskStep string) (string,
+	error,
+) {
 	bucket := source.Repository()
 	ctx = log.WithValues(ctx, "key", key)
 
-	log.Info(ctx, "Importing snapshot")
-	r, err := ec2Client.ImportSnapshot(ctx, &ec2.ImportSnapshotInput{
-		DiskContainer: &ec2types.SnapshotDiskContainer{
-			Description: &image,
-			Format:      ptr.P("raw"),
-			UserBucket: &ec2types.UserBucket{
-				S3Bucket: &bucket,
-				S3Key:    &key,
-			},
-		},
-		Encrypted: ptr.P(false),
+	taskID, err := journaled(ctx, taskStep, func() (string, error) {
+		log.Info(ctx, "Importing snapshot")
+		token := rand.Text()
+		r, err := retried(ctx, "import snapshot", func(ctx context.Context) (*ec2.ImportSnapshotOutput, error) {
+			return ec2Client.ImportSnapshot(ctx, &ec2.ImportSnapshotInput{
+				ClientToken: &token,
+				DiskContainer: &ec2types.SnapshotDiskContainer{
+					Description: &image,
+					Format:      ptr.P("raw"),
+					UserBucket: &ec2types.UserBucket{
+						S3Bucket: &bucket,
+						S3Key:    &key,
+					},
+				},
+				Encrypted: ptr.P(false),
+			})
+		})
+		if err != nil {
+			return "", fmt.Errorf("cannot import snapshot from %s in bucket %s: %w", key, bucket, err)
+		}
+		if r.ImportTaskId == nil {
+			return "", fmt.Errorf("cannot import snapshot from %s in bucket %s: missing import task ID", key, bucket)
+		}
+
+		return *r.ImportTaskId, nil
 	})
 	if err != nil {
-		return "", fmt.Errorf("cannot import snapshot from %s in bucket %s: %w", key, bucket, err)
-	}
-	if r.ImportTaskId == nil {
-		return "", fmt.Errorf("cannot import snapshot from %s in bucket %s: missing import task ID", key, bucket)
+		return "", err
 	}
-	ctx = log.WithValues(ctx, "taskId", *r.ImportTaskId)
+	ctx = log.WithValues(ctx, "taskId", taskID)
 
-	var snapshot string
-	status := "active"
-	for status == "active" {
-		log.Debug(ctx, "Waiting for snapshot")
-		var s *ec2.DescribeImportSnapshotTasksOutput
-		s, err = ec2Client.DescribeImportSnapshotTasks(ctx, &ec2.DescribeImportSnapshotTasksInput{
-			ImportTaskIds: []string{*r.ImportTaskId},
+	var snapshot, status string
+	err = poll(ctx, PollStepImport, "import snapshot task "+taskID, func(ctx context.Context) (bool, string, error) {
+		s, err := retried(ctx, "describe import snapshot task", func(ctx context.Context) (*ec2.DescribeImportSnapshotTasksOutput, error) {
+			return ec2Client.DescribeImportSnapshotTasks(ctx, &ec2.DescribeImportSnapshotTasksInput{
+				ImportTaskIds: []string{taskID},
+			})
 		})
 		if err != nil {
-			return "", fmt.Errorf("cannot describe import snapshot tasks with id %s: %w", *r.ImportTaskId, err)
+			return false, "", fmt.Errorf("cannot describe import snapshot tasks with id %s: %w", taskID, err)
 		}
 		if len(s.ImportSnapshotTasks) != 1 || s.NextToken != nil {
-			return "", fmt.Errorf("cannot describe import snapshot tasks with id %s: missing import snapshot tasks", *r.ImportTaskId)
+			return false, "", fmt.Errorf("cannot describe import snapshot tasks with id %s: missing import snapshot tasks", taskID)
 		}
 		task := s.ImportSnapshotTasks[0]
 		if task.SnapshotTaskDetail == nil || task.SnapshotTaskDetail.Status == nil || task.SnapshotTaskDetail.SnapshotId == nil {
-			return "", fmt.Errorf("cannot describe import snapshot tasks with id %s: missing import snapshot task detail", *r.ImportTaskId)
+			return false, "", fmt.Errorf("cannot describe import snapshot tasks with id %s: missing import snapshot task detail", taskID)
 		}
 		status = *task.SnapshotTaskDetail.Status
 		snapshot = *task.SnapshotTaskDetail.SnapshotId
 
-		if status == "active" {
-			time.Sleep(time.Second * 7)
-		}
+		return status != "active", status, nil
+	})
+	if err != nil {
+		return "", err
 	}
 	if status != "completed" {
 		return "", fmt.Errorf("unknown import task status %s from %s in bucket %s", status, key, bucket)
@@ -668,9 +1092,11 @@ func (*aws) importSnapshot(ctx context.Context, ec2Client *ec2.Client, source Ar
 
 func (*aws) attachTags(ctx context.Context, ec2Client *ec2.Client, obj string, tags []ec2types.Tag) error {
 	log.Debug(ctx, "Attaching tags", "object", obj)
-	_, err := ec2Client.CreateTags(ctx, &ec2.CreateTagsInput{
-		Resources: []string{obj},
-		Tags:      tags,
+	_, err := retried(ctx, "create tags", func(ctx context.Context) (*ec2.CreateTagsOutput, error) {
+		return ec2Client.CreateTags(ctx, &ec2.CreateTagsInput{
+			Resources: []string{obj},
+			Tags:      tags,
+		})
 	})
 	if err != nil {
 		return fmt.Errorf("cannot create tags for %s: %w", obj, err)
@@ -679,7 +1105,7 @@ func (*aws) attachTags(ctx context.Context, ec2Client *ec2.Client, obj string, t
 	return nil
 }
 
-func (*aws) registerImage(ctx context.Context, ec2Client *ec2.Client, snapshot, image string, arch ec2types.ArchitectureValues,
+func (p *aws) registerImage(ctx context.Context, ec2Client *ec2.Client, snapshot, image string, arch ec2types.ArchitectureValues,
 	requireUEFI bool, uefiData *string,
 ) (string, error) {
 	params := ec2.RegisterImageInput{
@@ -709,7 +1135,21 @@ func (*aws) registerImage(ctx context.Context, ec2Client *ec2.Client, snapshot,
 	}
 
 	log.Info(ctx, "Registering image")
-	r, err := ec2Client.RegisterImage(ctx, &params)
+	// RegisterImage does not take a client token, so an attempt which has timed out may still have registered the image. Retrying it
+	// would fail with a duplicate name and leave that image behind, so a retry adopts an image already registered under the name.
+	var attempted bool
+	r, err := retried(ctx, "register image", func(ctx context.Context) (*ec2.RegisterImageOutput, error) {
+		if attempted {
+			imageID, err := p.findImage(ctx, ec2Client, image)
+			if err != nil || imageID != "" {
+				return &ec2.RegisterImageOutput{
+					ImageId: &imageID,
+				}, err
+			}
+		}
+		attempted = true
+		return ec2Client.RegisterImage(ctx, &params)
+	})
 	if err != nil {
 		return "", fmt.Errorf("cannot register image: %w", err)
 	}
@@ -720,8 +1160,28 @@ func (*aws) registerImage(ctx context.Context, ec2Client *ec2.Client, snapshot,
 	return *r.ImageId, nil
 }
 
-func (*aws) copyImage(ctx context.Context, ec2Client *ec2.Client, image, imageID, fromRegion string,
-	toRegions []string,
+// findImage returns the ID of the image of the account which has a given name, or an empty string if there is none.
+func (*aws) findImage(ctx context.Context, ec2Client *ec2.Client, image string) (string, error) {
+	r, err := ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Owners: []string{"self"},
+		Filters: []ec2types.Filter{{
+			Name:   ptr.P("name"),
+			Values: []string{image},
+		}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot describe images named %s: %w", image, err)
+	}
+	if len(r.Images) == 0 || r.Images[0].ImageId == nil {
+		return "", nil
+	}
+	log.Info(ctx, "Adopting image registered by an earlier attempt", "imageID", *r.Images[0].ImageId)
+
+	return *r.Images[0].ImageId, nil
+}
+
+func (p *aws) copyImage(ctx context.Context, ec2Client *ec2.Client, image, imageID, fromRegion string,
+	toRegions []string, copyStep string,
 ) (map[string]string, error) {
 	images := make(map[string]string, len(toRegions))
 
@@ -731,48 +1191,180 @@ func (*aws) copyImage(ctx context.Context, ec2Client *ec2.Client, image, imageID
 			continue
 		}
 
-		log.Info(ctx, "Copying image", "toRegion", region)
-		r, err := ec2Client.CopyImage(ctx, &ec2.CopyImageInput{
+		copyID, err := journaled(ctx, copyStep+region, func() (string, error) {
+			log.Info(ctx, "Copying image", "toRegion", region)
+			return p.copyImageTo(ctx, ec2Client, image, imageID, fromRegion, region)
+		})
+		if err != nil {
+			return nil, err
+		}
+		p.trackImage(ctx, copyStep+region, ec2Client, copyID, region)
+		images[region] = copyID
+	}
+
+	return images, nil
+}
+
+// copyImageTo copies an image into a region of the account of the EC2 client, the image may belong to another account if it has been
+// shared with this one.
+func (*aws) copyImageTo(ctx context.Context, ec2Client *ec2.Client, image, imageID, fromRegion, region string) (string, error) {
+	token := rand.Text()
+	rctx := log.WithValues(ctx, "toRegion", region)
+	r, err := retried(rctx, "copy image", func(ctx context.Context) (*ec2.CopyImageOutput, error) {
+		return ec2Client.CopyImage(ctx, &ec2.CopyImageInput{
+			ClientToken:   &token,
 			Name:          &image,
 			SourceImageId: &imageID,
 			SourceRegion:  &fromRegion,
 			CopyImageTags: ptr.P(true),
 		}, overrideRegion(region))
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot copy image %s to region %s: %w", imageID, region, err)
+	}
+	if r.ImageId == nil {
+		return "", fmt.Errorf("cannot copy image %s to region %s: missing image ID", imageID, region)
+	}
+
+	return *r.ImageId, nil
+}
+
+// shareImage grants an account the permission to launch an image and to create volumes from its snapshots, which it needs to copy the
+// image.
+func (*aws) shareImage(ctx context.Context, ec2Client *ec2.Client, imageID, region, account string) error {
+	ctx = log.WithValues(ctx, "fromImageID", imageID, "fromRegion", region, "account", account)
+
+	r, err := retried(ctx, "describe images", func(ctx context.Context) (*ec2.DescribeImagesOutput, error) {
+		return ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+			ImageIds: []string{imageID},
+		}, overrideRegion(region))
+	})
+	if err != nil {
+		return fmt.Errorf("cannot describe image %s in region %s: %w", imageID, region, err)
+	}
+	if len(r.Images) != 1 {
+		return fmt.Errorf("cannot describe image %s in region %s: missing image", imageID, region)
+	}
+
+	log.Info(ctx, "Sharing image")
+	_, err = retried(ctx, "modify image attribute", func(ctx context.Context) (*ec2.ModifyImageAttributeOutput, error) {
+		return ec2Client.ModifyImageAttribute(ctx, &ec2.ModifyImageAttributeInput{
+			ImageId:   &imageID,
+			Attribute: ptr.P("launchPermission"),
+			LaunchPermission: &ec2types.LaunchPermissionModifications{
+				Add: []ec2types.LaunchPermission{
+					{
+						UserId: &account,
+					},
+				},
+			},
+		}, overrideRegion(region))
+	})
+	if err != nil {
+		return fmt.Errorf("cannot share image %s in region %s: %w", imageID, region, err)
+	}
+
+	for _, mapping := range r.Images[0].BlockDeviceMappings {
+		if mapping.Ebs == nil || mapping.Ebs.SnapshotId == nil {
+			continue
+		}
+		snapshot := *mapping.Ebs.SnapshotId
+
+		log.Debug(ctx, "Sharing snapshot", "snapshot", snapshot)
+		_, err = retried(ctx, "modify snapshot attribute", func(ctx context.Context) (*ec2.ModifySnapshotAttributeOutput, error) {
+			return ec2Client.ModifySnapshotAttribute(ctx, &ec2.ModifySnapshotAttributeInput{
+				SnapshotId: &snapshot,
+				Attribute:  ec2types.SnapshotAttributeNameCreateVolumePermission,
+				CreateVolumePermission: &ec2types.CreateVolumePermissionModifications{
+					Add: []ec2types.CreateVolumePermission{
+						{
+							UserId: &account,
+						},
+					},
+				},
+			}, overrideRegion(region))
+		})
 		if err != nil {
-			return nil, fmt.Errorf("cannot copy image %s to region %s: %w", imageID, region, err)
+			return fmt.Errorf("cannot share snapshot %s in region %s: %w", snapshot, region, err)
 		}
-		if r.ImageId == nil {
-			return nil, fmt.Errorf("cannot copy image %s to region %s: missing image ID", imageID, region)
+	}
+
+	return nil
+}
+
+// promotionSource finds the image to promote from for a cloud, preferably one in the given region, along with an EC2 client of the
+// account owning it.
+func (p *aws) promotionSource(manifest *gl.Manifest, cld, region string) (gl.AWSImage, *ec2.Client, bool) {
+	output := publishedImageMetadata(manifest.PublishedImageMetadata)
+
+	var ec2Client *ec2.Client
+	for _, target := range p.pubCfg.Targets {
+		if p.cloud(target) == cld {
+			ec2Client = p.tgtEC2Clients[target.Config]
+			break
 		}
-		images[region] = *r.ImageId
+	}
+	if ec2Client == nil {
+		return gl.AWSImage{}, nil, false
 	}
 
-	return images, nil
+	var found *gl.AWSImage
+	for _, img := range output.AWSImages {
+		if img.Cloud != cld {
+			continue
+		}
+		if img.Region == region {
+			return img, ec2Client, true
+		}
+		if found == nil {
+			found = &img
+		}
+	}
+	if found == nil {
+		return gl.AWSImage{}, nil, false
+	}
+
+	return *found, ec2Client, true
+}
+
+func (p *aws) accountID(ctx context.Context, config string) (string, error) {
+	r, err := retried(ctx, "get caller identity", func(ctx context.Context) (*sts.GetCallerIdentityOutput, error) {
+		return p.tgtSTSClients[config].GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot get caller identity: %w", err)
+	}
+	if r.Account == nil {
+		return "", errors.New("cannot get caller identity: missing account")
+	}
+
+	return *r.Account, nil
 }
 
 func (*aws) waitForImages(ctx context.Context, ec2Client *ec2.Client, images map[string]string) error {
 	for region, imageID := range images {
-		var state ec2types.ImageState
-		for state != ec2types.ImageStateAvailable {
-			log.Debug(ctx, "Waiting for image", "toRegion", region, "toImageID", imageID)
-			r, err := ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{
-				ImageIds: []string{imageID},
-			}, overrideRegion(region))
+		rctx := log.WithValues(ctx, "toRegion", region)
+		err := poll(rctx, PollStepCopy, "image "+imageID+" in region "+region, func(ctx context.Context) (bool, string, error) {
+			r, err := retried(ctx, "describe images", func(ctx context.Context) (*ec2.DescribeImagesOutput, error) {
+				return ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+					ImageIds: []string{imageID},
+				}, overrideRegion(region))
+			})
 			if err != nil {
-				return fmt.Errorf("cannot get status of image %s in region %s: %w", imageID, region, err)
+				return false, "", fmt.Errorf("cannot get status of image %s in region %s: %w", imageID, region, err)
 			}
 			if len(r.Images) != 1 || r.NextToken != nil {
-				return fmt.Errorf("cannot get status of image %s in region %s: missing images", imageID, region)
+				return false, "", fmt.Errorf("cannot get status of image %s in region %s: missing images", imageID, region)
 			}
-			state = r.Images[0].State
-
-			if state != ec2types.ImageStateAvailable {
-				if state != ec2types.ImageStatePending {
-					return fmt.Errorf("image %s in region %s has state %s", imageID, region, state)
-				}
-
-				time.Sleep(time.Second * 7)
+			state := r.Images[0].State
+			if state != ec2types.ImageStateAvailable && state != ec2types.ImageStatePending {
+				return false, "", fmt.Errorf("image %s in region %s has state %s", imageID, region, state)
 			}
+
+			return state == ec2types.ImageStateAvailable, string(state), nil
+		})
+		if err != nil {
+			return err
 		}
 	}
 	log.Info(ctx, "Images ready", "count", len(images))
@@ -783,17 +1375,20 @@ func (*aws) waitForImages(ctx context.Context, ec2Client *ec2.Client, images map
 func (*aws) makePublic(ctx context.Context, ec2Client *ec2.Client, images map[string]string) error {
 	for region, imageID := range images {
 		log.Debug(ctx, "Adding launch permission to image", "toRegion", region, "toImageID", imageID)
-		_, err := ec2Client.ModifyImageAttribute(ctx, &ec2.ModifyImageAttributeInput{
-			ImageId:   &imageID,
-			Attribute: ptr.P("launchPermission"),
-			LaunchPermission: &ec2types.LaunchPermissionModifications{
-				Add: []ec2types.LaunchPermission{
-					{
-						Group: ec2types.PermissionGroupAll,
+		rctx := log.WithValues(ctx, "toRegion", region)
+		_, err := retried(rctx, "modify image attribute", func(ctx context.Context) (*ec2.ModifyImageAttributeOutput, error) {
+			return ec2Client.ModifyImageAttribute(ctx, &ec2.ModifyImageAttributeInput{
+				ImageId:   &imageID,
+				Attribute: ptr.P("launchPermission"),
+				LaunchPermission: &ec2types.LaunchPermissionModifications{
+					Add: []ec2types.LaunchPermission{
+						{
+							Group: ec2types.PermissionGroupAll,
+						},
 					},
 				},
-			},
-		}, overrideRegion(region))
+			}, overrideRegion(region))
+		})
 		if err != nil {
 			return fmt.Errorf("cannot modify attribute of image %s in region %s: %w", imageID, region, err)
 		}
@@ -802,18 +1397,198 @@ func (*aws) makePublic(ctx context.Context, ec2Client *ec2.Client, images map[st
 	return nil
 }
 
+// withoutSDKRetries disables the retries of the SDK for an S3 call which is retried by retried. Calls streaming a body are left to the
+// SDK, which retries them only as long as the body can be rewound.
+func withoutSDKRetries(o *s3.Options) {
+	o.Retryer = awssdk.NopRetryer{}
+}
+
 func overrideRegion(region string) func(o *ec2.Options) {
 	return func(o *ec2.Options) {
 		o.Region = region
 	}
 }
 
+func (*aws) verifyImage(ctx context.Context, ec2Client *ec2.Client, img PublishedImage) (*ImageProblem, error) {
+	log.Debug(ctx, "Verifying image")
+	r, err := retried(ctx, "describe images", func(ctx context.Context) (*ec2.DescribeImagesOutput, error) {
+		return ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+			ImageIds: []string{img.ID},
+		}, overrideRegion(img.Region))
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && strings.HasPrefix(apiErr.ErrorCode(), "InvalidAMIID.") {
+			return &ImageProblem{
+				Image:   img,
+				Problem: ProblemDeleted,
+			}, nil
+		}
+		return nil, fmt.Errorf("cannot describe image: %w", err)
+	}
+	if len(r.Images) == 0 {
+		return &ImageProblem{
+			Image:   img,
+			Problem: ProblemDeleted,
+		}, nil
+	}
+	if len(r.Images) != 1 || r.NextToken != nil {
+		return nil, errors.New("cannot describe image: too many images")
+	}
+	state := r.Images[0].State
+	if state != ec2types.ImageStateAvailable {
+		return &ImageProblem{
+			Image:   img,
+			Problem: ProblemNotAvailable,
+			Detail:  "state " + string(state),
+		}, nil
+	}
+	if r.Images[0].Public == nil || !*r.Images[0].Public {
+		return &ImageProblem{
+			Image:   img,
+			Problem: ProblemPrivate,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (p *aws) trackImage(ctx context.Context, step string, ec2Client *ec2.Client, imageID, region string) {
+	track(ctx, step, "image "+imageID+" in region "+region, func(ctx context.Context) error {
+		return p.deregisterImage(ctx, ec2Client, imageID, region)
+	})
+}
+
+// awsSnapshotImageTag marks a snapshot imported by GLCI with the name of the image that is registered from it.
+const awsSnapshotImageTag = "gardenlinux-image"
+
+func (*aws) listOrphans(ctx context.Context, ec2Client *ec2.Client, cld, region string, withSnapshots bool,
+	referenced map[string]struct{},
+) ([]Resource, error) {
+	var orphans []Resource
+	inUse := make(map[string]struct{})
+
+	// All images of the account are listed, since a snapshot is in use as long as any image refers to it.
+	log.Debug(ctx, "Listing images")
+	images := ec2.NewDescribeImagesPaginator(ec2Client, &ec2.DescribeImagesInput{
+		Owners: []string{"self"},
+	})
+	for images.HasMorePages() {
+		r, err := retried(ctx, "describe images", func(ctx context.Context) (*ec2.DescribeImagesOutput, error) {
+			return images.NextPage(ctx, overrideRegion(region))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot describe images: %w", err)
+		}
+
+		for _, img := range r.Images {
+			if img.ImageId == nil {
+				return nil, errors.New("cannot describe images: missing image ID")
+			}
+			for _, mapping := range img.BlockDeviceMappings {
+				if mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+					inUse[*mapping.Ebs.SnapshotId] = struct{}{}
+				}
+			}
+
+			if !strings.HasPrefix(ptr.V(img.Name), "gardenlinux-") {
+				continue
+			}
+			_, ok := referenced[*img.ImageId]
+			if ok {
+				continue
+			}
+			var created time.Time
+			if img.CreationDate != nil {
+				created, err = time.Parse(time.RFC3339, *img.CreationDate)
+				if err != nil {
+					return nil, fmt.Errorf("invalid creation date of image %s: %w", *img.ImageId, err)
+				}
+			}
+			orphans = append(orphans, Resource{
+				Kind:    ResourceKindImage,
+				Cloud:   cld,
+				Region:  region,
+				ID:      *img.ImageId,
+				Name:    ptr.V(img.Name),
+				Created: created,
+			})
+		}
+	}
+
+	if !withSnapshots {
+		return orphans, nil
+	}
+
+	// Only snapshots which GLCI has tagged on import are considered, other snapshots of the account are never touched.
+	log.Debug(ctx, "Listing snapshots")
+	snapshots := ec2.NewDescribeSnapshotsPaginator(ec2Client, &ec2.DescribeSnapshotsInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   ptr.P("tag-key"),
+				Values: []string{awsSnapshotImageTag},
+			},
+		},
+		OwnerIds: []string{"self"},
+	})
+	for snapshots.HasMorePages() {
+		r, err := retried(ctx, "describe snapshots", func(ctx context.Context) (*ec2.DescribeSnapshotsOutput, error) {
+			return snapshots.NextPage(ctx, overrideRegion(region))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot describe snapshots: %w", err)
+		}
+
+		for _, snapshot := range r.Snapshots {
+			if snapshot.SnapshotId == nil {
+				return nil, errors.New("cannot describe snapshots: missing snapshot ID")
+			}
+			_, ok := inUse[*snapshot.SnapshotId]
+			if ok {
+				continue
+			}
+			var name string
+			for _, tag := range snapshot.Tags {
+				if ptr.V(tag.Key) == awsSnapshotImageTag {
+					name = ptr.V(tag.Value)
+				}
+			}
+			orphans = append(orphans, Resource{
+				Kind:    ResourceKindSnapshot,
+				Cloud:   cld,
+				Region:  region,
+				ID:      *snapshot.SnapshotId,
+				Name:    name,
+				Created: ptr.V(snapshot.StartTime),
+			})
+		}
+	}
+
+	return orphans, nil
+}
+
+func (*aws) deleteSnapshot(ctx context.Context, ec2Client *ec2.Client, snapshot string) error {
+	log.Info(ctx, "Deleting snapshot")
+	_, err := retried(ctx, "delete snapshot", func(ctx context.Context) (*ec2.DeleteSnapshotOutput, error) {
+		return ec2Client.DeleteSnapshot(ctx, &ec2.DeleteSnapshotInput{
+			SnapshotId: &snapshot,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete snapshot %s: %w", snapshot, err)
+	}
+
+	return nil
+}
+
 func (*aws) deregisterImage(ctx context.Context, ec2Client *ec2.Client, imageID, region string) error {
 	log.Info(ctx, "Deregistering image")
-	r, err := ec2Client.DeregisterImage(ctx, &ec2.DeregisterImageInput{
-		ImageId:                   &imageID,
-		DeleteAssociatedSnapshots: ptr.P(true),
-	}, overrideRegion(region))
+	r, err := retried(ctx, "deregister image", func(ctx context.Context) (*ec2.DeregisterImageOutput, error) {
+		return ec2Client.DeregisterImage(ctx, &ec2.DeregisterImageInput{
+			ImageId:                   &imageID,
+			DeleteAssociatedSnapshots: ptr.P(true),
+		}, overrideRegion(region))
+	})
 	if err != nil {
 		return fmt.Errorf("cannot deregister image %s: %w", imageID, err)
 	}
@@ -3,6 +3,7 @@ package cloudprovider
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -19,6 +20,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v7"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/pageblob"
 	"github.com/Masterminds/semver/v3"
 
 	"github.com/gardenlinux/glci/internal/env"
@@ -179,22 +181,23 @@ func (*azure) ImageSuffix() string {
 	return ".vhd"
 }
 
+func (*azure) Platforms() []string {
+	return []string{"azure"}
+}
+
+func (p *azure) Clouds() []string {
+	return []string{p.cloud()}
+}
+
 func (p *azure) IsPublished(manifest *gl.Manifest) (bool, error) {
 	if !p.isConfigured() {
 		return false, errors.New("config not set")
 	}
 
-	azureOutput, err := publishingOutputFromManifest[azurePublishingOutput](manifest)
-	if err != nil {
-		return false, err
-	}
-
+	azureOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
 	cld := p.cloud()
 
-	if azureOutput.Images == nil {
-		return false, nil
-	}
-	for _, img := range *azureOutput.Images {
+	for _, img := range azureOutput.AzureImages {
 		if img.Cloud == cld {
 			return true, nil
 		}
@@ -203,43 +206,50 @@ func (p *azure) IsPublished(manifest *gl.Manifest) (bool, error) {
 	return false, nil
 }
 
-func (p *azure) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
+func (p *azure) PublishedImages(manifest *gl.Manifest) ([]PublishedImage, error) {
 	if !p.isConfigured() {
 		return nil, errors.New("config not set")
 	}
 
-	azureOutput, err := publishingOutput[azurePublishingOutput](output)
-	if err != nil {
-		return nil, err
+	azureOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
+	cld := p.cloud()
+
+	var images []PublishedImage
+	for _, img := range azureOutput.AzureImages {
+		if img.Cloud == cld {
+			images = append(images, PublishedImage{
+				Cloud: img.Cloud,
+				ID:    img.ID,
+			})
+		}
 	}
-	var ownOutput azurePublishingOutput
-	ownOutput, err = publishingOutput[azurePublishingOutput](own)
-	if err != nil {
-		return nil, err
+
+	return images, nil
+}
+
+func (p *azure) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
 
+	azureOutput := publishedImageMetadata(output)
+	ownOutput := publishedImageMetadata(own)
 	cld := p.cloud()
 
-	if ownOutput.Images == nil {
-		ownOutput.Images = &[]azurePublishedImage{}
-	}
-	for _, img := range *ownOutput.Images {
+	for _, img := range ownOutput.AzureImages {
 		if img.Cloud != cld {
 			return nil, errors.New("new publishing output has extraneous entries")
 		}
 	}
 
-	if azureOutput.Images == nil {
-		return &ownOutput, nil
-	}
-	for _, img := range *azureOutput.Images {
+	for _, img := range azureOutput.AzureImages {
 		if img.Cloud == cld {
 			return nil, errors.New("cannot add publishing output to existing publishing output")
 		}
 	}
 
-	ownOutput.Images = ptr.P(slices.Concat(*azureOutput.Images, *ownOutput.Images))
-	return &ownOutput, nil
+	azureOutput.AzureImages = slices.Concat(azureOutput.AzureImages, ownOutput.AzureImages)
+	return &azureOutput, nil
 }
 
 func (p *azure) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutput, error) {
@@ -247,28 +257,18 @@ func (p *azure) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOu
 		return nil, errors.New("config not set")
 	}
 
-	azureOutput, err := publishingOutput[azurePublishingOutput](output)
-	if err != nil {
-		return nil, err
-	}
-
+	azureOutput := publishedImageMetadata(output)
 	cld := p.cloud()
 
-	var otherImages []azurePublishedImage
-	if azureOutput.Images != nil {
-		for _, img := range *azureOutput.Images {
-			if img.Cloud != cld {
-				otherImages = append(otherImages, img)
-			}
+	var otherImages []gl.AzureImage
+	for _, img := range azureOutput.AzureImages {
+		if img.Cloud != cld {
+			otherImages = append(otherImages, img)
 		}
 	}
-	if len(otherImages) == 0 {
-		return nil, nil
-	}
+	azureOutput.AzureImages = otherImages
 
-	return &azurePublishingOutput{
-		Images: &otherImages,
-	}, nil
+	return remainingPublishingOutput(azureOutput), nil
 }
 
 func (p *azure) Publish(ctx context.Context, cname string, manifest *gl.Manifest, sources map[string]ArtifactSource) (PublishingOutput,
@@ -308,15 +308,16 @@ func (p *azure) Publish(ctx context.Context, cname string, manifest *gl.Manifest
 	ctx = log.WithValues(ctx, "requireUEFI", requireUEFI, "secureBoot", secureBoot)
 
 	var regions []string
-	regions, err = p.listRegions(ctx)
+	regions, err = p.publishingRegions(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("cannot list regions: %w", err)
-	}
-	if p.pubCfg.Regions != nil {
-		regions = slc.Subset(regions, *p.pubCfg.Regions)
+		return nil, err
 	}
-	if len(regions) == 0 {
-		return nil, errors.New("no available regions")
+
+	_, err = journaled(ctx, "checksum", func() (string, error) {
+		return imagePath.S3Key, verifyReleaseFile(ctx, source, imagePath)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify image %s: %w", image, err)
 	}
 
 	imageDefinition := p.sku(gallery.Image, cname, false)
@@ -336,57 +337,84 @@ func (p *azure) Publish(ctx context.Context, cname string, manifest *gl.Manifest
 		return nil, fmt.Errorf("cannot create image definition %s for image %s: %w", imageDefinition, image, err)
 	}
 
-	var blob, blobURL string
-	blob, blobURL, err = p.importBlob(ctx, source, imagePath.S3Key, image)
+	var blob string
+	blob, err = journaled(ctx, "blob", func() (string, error) {
+		return p.importBlob(ctx, source, imagePath.S3Key, image)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot upload blob for image %s: %w", image, err)
 	}
+	track(ctx, "blob", "blob "+blob, func(ctx context.Context) error {
+		return p.deleteBlob(ctx, blob)
+	})
+	blobURL := p.blobClient(blob).URL()
 
-	outputImages := make([]azurePublishedImage, 0, 2)
+	outputImages := make([]gl.AzureImage, 0, 2)
 	if bios {
-		imageID, err = p.createImage(ctx, &gallery, blobURL, image, true)
+		imageID, err = journaled(ctx, "image_bios", func() (string, error) {
+			return p.createImage(ctx, &gallery, blobURL, image, true)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("cannot create image: %w", err)
 		}
+		p.trackImage(ctx, "image_bios", gallery.ResourceGroup, p.imageResourceName(image, true))
 
-		err = p.createImageVersion(ctx, &gallery, imageDefinitionBIOS, imageVersion, imageID, regions, false, "", "", "")
+		_, err = journaled(ctx, "image_version_bios", func() (string, error) {
+			return imageVersion, p.createImageVersion(ctx, &gallery, imageDefinitionBIOS, imageVersion,
+				&armcompute.GalleryArtifactVersionFullSource{
+					ID: &imageID,
+				}, regions, false, "", "", "")
+		})
 		if err != nil {
 			return nil, fmt.Errorf("cannot create image version %s for image %s: %w", imageVersion, image, err)
 		}
+		p.trackImageVersion(ctx, "image_version_bios", &gallery, imageDefinitionBIOS, imageVersion)
 
 		publicID, err = p.getPublicID(ctx, &gallery, imageDefinitionBIOS, imageVersion)
 		if err != nil {
 			return nil, fmt.Errorf("cannot get public ID of %s for image %s: %w", imageVersion, image, err)
 		}
 
-		outputImages = append(outputImages, azurePublishedImage{
+		outputImages = append(outputImages, gl.AzureImage{
 			Cloud: p.cloud(),
 			ID:    publicID,
 			Gen:   "V1",
 		})
 	}
 
-	imageID, err = p.createImage(ctx, &gallery, blobURL, image, false)
+	imageID, err = journaled(ctx, "image", func() (string, error) {
+		return p.createImage(ctx, &gallery, blobURL, image, false)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot create image %s: %w", image, err)
 	}
+	p.trackImage(ctx, "image", gallery.ResourceGroup, p.imageResourceName(image, false))
 
-	err = p.deleteBlob(ctx, blob)
+	_, err = journaled(ctx, "blob_deleted", func() (string, error) {
+		return blob, p.deleteBlob(ctx, blob)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot delete blob for image %s: %w", image, err)
 	}
+	untrack(ctx, "blob")
 
-	err = p.createImageVersion(ctx, &gallery, imageDefinition, imageVersion, imageID, regions, secureBoot, pk, kek, db)
+	_, err = journaled(ctx, "image_version", func() (string, error) {
+		return imageVersion, p.createImageVersion(ctx, &gallery, imageDefinition, imageVersion,
+			&armcompute.GalleryArtifactVersionFullSource{
+				ID: &imageID,
+			}, regions, secureBoot, pk, kek, db)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot create image version %s for image %s: %w", imageVersion, image, err)
 	}
+	p.trackImageVersion(ctx, "image_version", &gallery, imageDefinition, imageVersion)
 
 	publicID, err = p.getPublicID(ctx, &gallery, imageDefinition, imageVersion)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get public ID of %s for image %s: %w", imageVersion, image, err)
 	}
 
-	outputImages = append(outputImages, azurePublishedImage{
+	outputImages = append(outputImages, gl.AzureImage{
 		Cloud: p.cloud(),
 		ID:    publicID,
 		Gen:   "V2",
@@ -394,22 +422,338 @@ func (p *azure) Publish(ctx context.Context, cname string, manifest *gl.Manifest
 
 	log.Info(ctx, "Image ready")
 
-	return &azurePublishingOutput{
-		Images: &outputImages,
+	return &gl.PublishedImageMetadata{
+		AzureImages: outputImages,
 	}, nil
 }
 
-func (p *azure) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]ArtifactSource) error {
+func (p *azure) Promote(ctx context.Context, cname string, manifest *gl.Manifest, from PublishingTarget, fromManifest *gl.Manifest,
+	sources map[string]ArtifactSource,
+) (PublishingOutput, error) {
 	if !p.isConfigured() {
-		return errors.New("config not set")
+		return nil, errors.New("config not set")
+	}
+
+	_, ok := from.(*azure)
+	if !ok {
+		return nil, fmt.Errorf("cannot promote from %s", from.Type())
+	}
+	fromOutput := publishedImageMetadata(fromManifest.PublishedImageMetadata)
+	fromImages := make(map[string]string, 2)
+	for _, img := range fromOutput.AzureImages {
+		if img.Cloud == p.cloud() {
+			fromImages[img.Gen] = img.ID
+		}
+	}
+	if len(fromImages) == 0 {
+		log.Info(ctx, "No source image in cloud, importing image", "cloud", p.cloud())
+		return p.Publish(ctx, cname, manifest, sources)
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	image := p.imageName(cname, manifest.Version, manifest.BuildCommittish)
+	imageVersion, err := p.version(manifest.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %s: %w", manifest.Version, err)
+	}
+	var arch armcompute.Architecture
+	arch, err = p.architecture(manifest.Architecture)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", cname, err)
+	}
+	source := sources[p.pubCfg.Source]
+	gallery := p.galleryCreds[p.pubCfg.GalleryConfig]
+	ctx = log.WithValues(ctx, "image", image, "architecture", arch)
+
+	var requireUEFI, secureBoot bool
+	var pk, kek, db string
+	requireUEFI, secureBoot, pk, kek, db, err = p.prepareSecureBoot(ctx, source, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prepare secureboot: %w", err)
+	}
+	bios := arch == armcompute.ArchitectureX64 && !requireUEFI && !secureBoot
+	ctx = log.WithValues(ctx, "requireUEFI", requireUEFI, "secureBoot", secureBoot)
+
+	if fromImages["V2"] == "" || (bios && fromImages["V1"] == "") {
+		return nil, fmt.Errorf("missing source image for %s in cloud %s", cname, p.cloud())
+	}
+
+	var regions []string
+	regions, err = p.publishingRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imageDefinition := p.sku(gallery.Image, cname, false)
+	var imageDefinitionBIOS, publicID string
+
+	outputImages := make([]gl.AzureImage, 0, 2)
+	if bios {
+		imageDefinitionBIOS = p.sku(gallery.Image, cname, true)
+
+		err = p.createImageDefinition(ctx, &gallery, imageDefinitionBIOS, cname, arch, true, false)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create image definition %s for image %s: %w", imageDefinitionBIOS, image, err)
+		}
+
+		_, err = journaled(ctx, "image_version_bios", func() (string, error) {
+			return imageVersion, p.createImageVersion(ctx, &gallery, imageDefinitionBIOS, imageVersion,
+				&armcompute.GalleryArtifactVersionFullSource{
+					CommunityGalleryImageID: ptr.P(fromImages["V1"]),
+				}, regions, false, "", "", "")
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot create image version %s for image %s from %s: %w", imageVersion, image, fromImages["V1"], err)
+		}
+		p.trackImageVersion(ctx, "image_version_bios", &gallery, imageDefinitionBIOS, imageVersion)
+
+		publicID, err = p.getPublicID(ctx, &gallery, imageDefinitionBIOS, imageVersion)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get public ID of %s for image %s: %w", imageVersion, image, err)
+		}
+
+		outputImages = append(outputImages, gl.AzureImage{
+			Cloud: p.cloud(),
+			ID:    publicID,
+			Gen:   "V1",
+		})
+	}
+
+	err = p.createImageDefinition(ctx, &gallery, imageDefinition, cname, arch, false, secureBoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create image definition %s for image %s: %w", imageDefinition, image, err)
+	}
+
+	_, err = journaled(ctx, "image_version", func() (string, error) {
+		return imageVersion, p.createImageVersion(ctx, &gallery, imageDefinition, imageVersion,
+			&armcompute.GalleryArtifactVersionFullSource{
+				CommunityGalleryImageID: ptr.P(fromImages["V2"]),
+			}, regions, secureBoot, pk, kek, db)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create image version %s for image %s from %s: %w", imageVersion, image, fromImages["V2"], err)
+	}
+	p.trackImageVersion(ctx, "image_version", &gallery, imageDefinition, imageVersion)
+
+	publicID, err = p.getPublicID(ctx, &gallery, imageDefinition, imageVersion)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get public ID of %s for image %s: %w", imageVersion, image, err)
+	}
+
+	outputImages = append(outputImages, gl.AzureImage{
+		Cloud: p.cloud(),
+		ID:    publicID,
+		Gen:   "V2",
+	})
+
+	log.Info(ctx, "Image ready")
+
+	return &gl.PublishedImageMetadata{
+		AzureImages: outputImages,
+	}, nil
+}
+
+func (p *azure) Verify(ctx context.Context, manifest *gl.Manifest) ([]ImageProblem, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
 	ctx = log.WithValues(ctx, "target", p.Type())
 
-	pubOut, err := publishingOutputFromManifest[azurePublishingOutput](manifest)
+	images, err := p.PublishedImages(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	var regions []string
+	regions, err = p.listRegions(ctx)
 	if err != nil {
-		return fmt.Errorf("invalid manifest: %w", err)
+		return nil, fmt.Errorf("cannot list regions: %w", err)
+	}
+	if p.pubCfg.Regions != nil {
+		regions = slc.Subset(regions, *p.pubCfg.Regions)
 	}
-	if pubOut.Images == nil {
+
+	gallery := p.galleryCreds[p.pubCfg.GalleryConfig]
+	var problems []ImageProblem
+	for _, img := range images {
+		var imageProblems []ImageProblem
+		imageProblems, err = p.verifyImage(log.WithValues(ctx, "imageID", img.ID), &gallery, img, regions)
+		if err != nil {
+			return nil, fmt.Errorf("cannot verify image %s: %w", img.ID, err)
+		}
+		problems = append(problems, imageProblems...)
+	}
+
+	return problems, nil
+}
+
+func (p *azure) SetFilter(ctx context.Context, filter Filter) (bool, error) {
+	if !p.isConfigured() {
+		return false, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type(), "cloud", p.cloud())
+
+	if !filter.matchesCloud(p.cloud()) {
+		log.Info(ctx, "Excluding cloud")
+		return false, nil
+	}
+
+	// Manifests do not record the regions an image version is replicated to, so a publication restricted to some regions could not be
+	// told apart from a complete one.
+	if len(filter.Regions) > 0 {
+		log.Info(ctx, "Excluding cloud, image versions are replicated as a whole and cannot be selected by region")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (p *azure) Orphans(ctx context.Context, published []PublishedImage) ([]Resource, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
+	}
+	cld := p.cloud()
+	ctx = log.WithValues(ctx, "target", p.Type(), "cloud", cld)
+
+	referenced := make(map[string]struct{}, len(published))
+	for _, img := range published {
+		parts := strings.Split(img.ID, "/")
+		if img.Cloud == cld && len(parts) == 7 {
+			referenced[strings.ToLower(parts[4]+"/"+parts[6])] = struct{}{}
+		}
+	}
+
+	gallery := p.galleryCreds[p.pubCfg.GalleryConfig]
+	orphans, inUse, err := p.listOrphanedImageVersions(ctx, &gallery, referenced)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug(ctx, "Listing images")
+	images := p.imagesClient.NewListByResourceGroupPager(gallery.ResourceGroup, nil)
+	for images.More() {
+		var page armcompute.ImagesClientListByResourceGroupResponse
+		page, err = images.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list images: %w", err)
+		}
+
+		for _, image := range page.Value {
+			if image.ID == nil || image.Name == nil {
+				return nil, errors.New("cannot list images: missing ID or name")
+			}
+			if !strings.HasPrefix(*image.Name, "gardenlinux-") {
+				continue
+			}
+			_, ok := inUse[strings.ToLower(*image.ID)]
+			if ok {
+				continue
+			}
+			var created time.Time
+			if image.SystemData != nil {
+				created = ptr.V(image.SystemData.CreatedAt)
+			}
+			orphans = append(orphans, Resource{
+				Kind:    ResourceKindImage,
+				Cloud:   cld,
+				ID:      *image.Name,
+				Name:    *image.Name,
+				Created: created,
+			})
+		}
+	}
+
+	// Blobs are only needed while an image is being created, any remaining blob is a leftover.
+	container := p.storageAccountCreds[p.pubCfg.StorageAccountConfig].Container
+	log.Debug(ctx, "Listing blobs", "container", container)
+	blobs := p.storageClient.NewListBlobsFlatPager(container, &azblob.ListBlobsFlatOptions{
+		Prefix: ptr.P("gardenlinux-"),
+	})
+	for blobs.More() {
+		var page azblob.ListBlobsFlatResponse
+		page, err = blobs.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list blobs in container %s: %w", container, err)
+		}
+		if page.Segment == nil {
+			continue
+		}
+
+		for _, blob := range page.Segment.BlobItems {
+			if blob.Name == nil {
+				return nil, fmt.Errorf("cannot list blobs in container %s: missing name", container)
+			}
+			var created time.Time
+			if blob.Properties != nil {
+				created = ptr.V(blob.Properties.CreationTime)
+			}
+			orphans = append(orphans, Resource{
+				Kind:    ResourceKindBlob,
+				Cloud:   cld,
+				ID:      *blob.Name,
+				Name:    *blob.Name,
+				Created: created,
+			})
+		}
+	}
+
+	return orphans, nil
+}
+
+func (p *azure) DeleteOrphan(ctx context.Context, resource Resource) error {
+	if !p.isConfigured() {
+		return errors.New("config not set")
+	}
+	cld := p.cloud()
+	ctx = log.WithValues(ctx, "target", p.Type(), "cloud", cld)
+
+	if resource.Cloud != cld {
+		return fmt.Errorf("unknown cloud %s", resource.Cloud)
+	}
+	gallery := p.galleryCreds[p.pubCfg.GalleryConfig]
+
+	switch resource.Kind {
+	case ResourceKindImageVersion:
+		imageDefinition, imageVersion, ok := strings.Cut(resource.ID, "/")
+		if !ok {
+			return fmt.Errorf("invalid image version %s", resource.ID)
+		}
+		ctx = log.WithValues(ctx, "imageDefinition", imageDefinition, "imageVersion", imageVersion)
+
+		imageResourceGroup, image, err := p.deleteImageVersion(ctx, &gallery, imageDefinition, imageVersion)
+		if err != nil {
+			return fmt.Errorf("cannot delete image version %s for image definition %s: %w", imageVersion, imageDefinition, err)
+		}
+
+		err = p.deleteImage(log.WithValues(ctx, "imageResourceGroup", imageResourceGroup, "image", image), imageResourceGroup, image)
+		if err != nil {
+			return fmt.Errorf("cannot delete image %s: %w", image, err)
+		}
+
+		err = p.deleteEmptyImageDefinition(ctx, &gallery, imageDefinition)
+		if err != nil {
+			return fmt.Errorf("cannot delete image definition %s: %w", imageDefinition, err)
+		}
+
+		return nil
+	case ResourceKindImage:
+		return p.deleteImage(log.WithValues(ctx, "imageResourceGroup", gallery.ResourceGroup, "image", resource.ID), gallery.ResourceGroup,
+			resource.ID)
+	case ResourceKindBlob:
+		return p.deleteBlob(ctx, resource.ID)
+	default:
+		return fmt.Errorf("unsupported resource kind %s", resource.Kind)
+	}
+}
+
+func (p *azure) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]ArtifactSource) error {
+	if !p.isConfigured() {
+		return errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	pubOut := publishedImageMetadata(manifest.PublishedImageMetadata)
+	if len(pubOut.AzureImages) == 0 {
 		return errors.New("invalid manifest: missing published images")
 	}
 
@@ -417,14 +761,13 @@ func (p *azure) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]
 	cld := p.cloud()
 	ctx = log.WithValues(ctx, "cloud", cld)
 
-	for _, img := range *pubOut.Images {
+	for _, img := range pubOut.AzureImages {
 		if img.Cloud != cld {
 			continue
 		}
 		lctx := log.WithValues(ctx, "imageID", img.ID)
 
-		var imageDefinition, imageVersion string
-		imageDefinition, imageVersion, err = p.unpackPublicID(lctx, &gallery, img.ID)
+		imageDefinition, imageVersion, err := p.unpackPublicID(lctx, &gallery, img.ID)
 		if err != nil {
 			return err
 		}
@@ -505,16 +848,6 @@ type azurePublishingConfig struct {
 	china                  bool
 }
 
-type azurePublishingOutput struct {
-	Images *[]azurePublishedImage `yaml:"published_gallery_images,omitempty"`
-}
-
-type azurePublishedImage struct {
-	Cloud string `yaml:"azure_cloud"`
-	ID    string `yaml:"community_gallery_image_id"`
-	Gen   string `yaml:"hyper_v_generation"`
-}
-
 func (p *azure) isConfigured() bool {
 	return p.storageClient != nil && p.subscriptionsClient != nil && p.imagesClient != nil && p.galleryImagesClient != nil &&
 		p.galleryImageVersionsClient != nil && p.galleriesClient != nil && p.communityGalleryImageVersionsClient != nil
@@ -565,7 +898,7 @@ func (*azure) prepareSecureBoot(ctx context.Context, source ArtifactSource, mani
 		}
 
 		var rawPK []byte
-		rawPK, err = getObjectBytes(ctx, source, pkFile.S3Key)
+		rawPK, err = getObjectBytes(ctx, source, pkFile)
 		if err != nil {
 			return false, false, "", "", "", fmt.Errorf("cannot get PK: %w", err)
 		}
@@ -578,7 +911,7 @@ func (*azure) prepareSecureBoot(ctx context.Context, source ArtifactSource, mani
 		}
 
 		var rawKEK []byte
-		rawKEK, err = getObjectBytes(ctx, source, kekFile.S3Key)
+		rawKEK, err = getObjectBytes(ctx, source, kekFile)
 		if err != nil {
 			return false, false, "", "", "", fmt.Errorf("cannot get KEK: %w", err)
 		}
@@ -591,7 +924,7 @@ func (*azure) prepareSecureBoot(ctx context.Context, source ArtifactSource, mani
 		}
 
 		var rawDB []byte
-		rawDB, err = getObjectBytes(ctx, source, dbFile.S3Key)
+		rawDB, err = getObjectBytes(ctx, source, dbFile)
 		if err != nil {
 			return false, false, "", "", "", fmt.Errorf("cannot get DB: %w", err)
 		}
@@ -627,6 +960,22 @@ func (p *azure) listRegions(ctx context.Context) ([]string, error) {
 	return regions, nil
 }
 
+// publishingRegions returns the regions an image version is replicated to.
+func (p *azure) publishingRegions(ctx context.Context) ([]string, error) {
+	regions, err := p.listRegions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list regions: %w", err)
+	}
+	if p.pubCfg.Regions != nil {
+		regions = slc.Subset(regions, *p.pubCfg.Regions)
+	}
+	if len(regions) == 0 {
+		return nil, errors.New("no available regions")
+	}
+
+	return regions, nil
+}
+
 func (*azure) sku(base, cname string, bios bool) string {
 	cname = strings.TrimPrefix(cname, "azure-")
 	if bios {
@@ -662,7 +1011,9 @@ func (p *azure) createImageDefinition(ctx context.Context, gallery *azureGallery
 
 	log.Debug(ctx, "Getting image definition")
 	exists := true
-	_, err := p.galleryImagesClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, nil)
+	_, err := retried(ctx, "get image definition", func(ctx context.Context) (armcompute.GalleryImagesClientGetResponse, error) {
+		return p.galleryImagesClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, nil)
+	})
 	if err != nil {
 		var rerr *azcore.ResponseError
 		if !errors.As(err, &rerr) || rerr.StatusCode != http.StatusNotFound {
@@ -676,25 +1027,29 @@ func (p *azure) createImageDefinition(ctx context.Context, gallery *azureGallery
 
 	log.Info(ctx, "Creating image definition")
 	var poller *runtime.Poller[armcompute.GalleryImagesClientCreateOrUpdateResponse]
-	poller, err = p.galleryImagesClient.BeginCreateOrUpdate(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition,
-		armcompute.GalleryImage{
-			Location: &gallery.Region,
-			Properties: &armcompute.GalleryImageProperties{
-				Identifier: &armcompute.GalleryImageIdentifier{
-					Offer:     &gallery.Offer,
-					Publisher: &gallery.Publisher,
-					SKU:       ptr.P(p.sku(gallery.SKU, cname, bios)),
+	poller, err = retried(ctx, "create image definition", func(ctx context.Context) (
+		*runtime.Poller[armcompute.GalleryImagesClientCreateOrUpdateResponse], error,
+	) {
+		return p.galleryImagesClient.BeginCreateOrUpdate(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition,
+			armcompute.GalleryImage{
+				Location: &gallery.Region,
+				Properties: &armcompute.GalleryImageProperties{
+					Identifier: &armcompute.GalleryImageIdentifier{
+						Offer:     &gallery.Offer,
+						Publisher: &gallery.Publisher,
+						SKU:       ptr.P(p.sku(gallery.SKU, cname, bios)),
+					},
+					OSState:          ptr.P(armcompute.OperatingSystemStateTypesGeneralized),
+					OSType:           ptr.P(armcompute.OperatingSystemTypesLinux),
+					Architecture:     &arch,
+					Description:      &gallery.Description,
+					Eula:             &gallery.EULA,
+					Features:         features,
+					HyperVGeneration: &gen,
+					ReleaseNoteURI:   &gallery.ReleaseNoteURI,
 				},
-				OSState:          ptr.P(armcompute.OperatingSystemStateTypesGeneralized),
-				OSType:           ptr.P(armcompute.OperatingSystemTypesLinux),
-				Architecture:     &arch,
-				Description:      &gallery.Description,
-				Eula:             &gallery.EULA,
-				Features:         features,
-				HyperVGeneration: &gen,
-				ReleaseNoteURI:   &gallery.ReleaseNoteURI,
-			},
-		}, nil)
+			}, nil)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot create or update image definition %s: %w", imageDefinition, err)
 	}
@@ -709,69 +1064,86 @@ func (p *azure) createImageDefinition(ctx context.Context, gallery *azureGallery
 	return nil
 }
 
-func (p *azure) importBlob(ctx context.Context, source ArtifactSource, key, image string) (string, string, error) {
+func (p *azure) blobClient(blob string) *pageblob.Client {
+	container := p.storageAccountCreds[p.pubCfg.StorageAccountConfig].Container
+	return p.storageClient.ServiceClient().NewContainerClient(container).NewPageBlobClient(blob)
+}
+
+func (p *azure) importBlob(ctx context.Context, source ArtifactSource, key, image string) (string, error) {
 	container := p.storageAccountCreds[p.pubCfg.StorageAccountConfig].Container
 	blob := image + p.ImageSuffix()
 	size, err := source.GetObjectSize(ctx, key)
 	if err != nil {
-		return "", "", fmt.Errorf("cannot get object size: %w", err)
+		return "", fmt.Errorf("cannot get object size: %w", err)
 	}
 	ctx = log.WithValues(ctx, "key", key, "container", container, "blob", blob, "size", size)
 
 	log.Info(ctx, "Uploading blob")
 	srcURL := source.GetObjectURL(key)
-	blobClient := p.storageClient.ServiceClient().NewContainerClient(container).NewPageBlobClient(blob)
-	_, err = blobClient.Create(ctx, size, nil)
+	blobClient := p.blobClient(blob)
+	_, err = retried(ctx, "create blob", func(ctx context.Context) (pageblob.CreateResponse, error) {
+		return blobClient.Create(ctx, size, nil)
+	})
 	if err != nil {
-		return "", "", fmt.Errorf("cannot create blob: %w", err)
+		return "", fmt.Errorf("cannot create blob: %w", err)
 	}
 	var offset int64
 	for offset < size {
 		block := min(size-offset, 4*1024*1024)
-		_, err = blobClient.UploadPagesFromURL(ctx, srcURL, offset, offset, block, nil)
+		_, err = retried(ctx, "upload pages", func(ctx context.Context) (pageblob.UploadPagesFromURLResponse, error) {
+			return blobClient.UploadPagesFromURL(ctx, srcURL, offset, offset, block, nil)
+		})
 		if err != nil {
-			return "", "", fmt.Errorf("cannot upload to blob %s in container %s: %w", blob, container, err)
+			return "", fmt.Errorf("cannot upload to blob %s in container %s: %w", blob, container, err)
 		}
 		offset += block
 	}
 	log.Debug(ctx, "Blob uploaded")
 
-	return blob, blobClient.URL(), nil
+	return blob, nil
+}
+
+func (p *azure) imageResourceName(image string, bios bool) string {
+	if bios {
+		image += "-bios"
+	}
+
+	return image + p.ImageSuffix()
 }
 
 func (p *azure) createImage(ctx context.Context, gallery *azureGalleryCredentials, blobURL, image string, bios bool) (string, error) {
-	imageName := image
+	imageName := p.imageResourceName(image, bios)
 	gen := armcompute.HyperVGenerationTypesV2
 	if bios {
-		imageName += "-bios"
 		gen = armcompute.HyperVGenerationTypesV1
 	}
-	imageName += p.ImageSuffix()
 	ctx = log.WithValues(ctx, "imageName", imageName)
 
 	log.Info(ctx, "Creating image")
-	poller, err := p.imagesClient.BeginCreateOrUpdate(ctx, gallery.ResourceGroup, imageName, armcompute.Image{
-		Location: &gallery.Region,
-		Properties: &armcompute.ImageProperties{
-			HyperVGeneration: &gen,
-			StorageProfile: &armcompute.ImageStorageProfile{
-				OSDisk: &armcompute.ImageOSDisk{
-					OSState: ptr.P(armcompute.OperatingSystemStateTypesGeneralized),
-					OSType:  ptr.P(armcompute.OperatingSystemTypesLinux),
-					BlobURI: &blobURL,
-					Caching: ptr.P(armcompute.CachingTypesReadWrite),
+	poller, err := retried(ctx, "create image", func(ctx context.Context) (
+		*runtime.Poller[armcompute.ImagesClientCreateOrUpdateResponse], error,
+	) {
+		return p.imagesClient.BeginCreateOrUpdate(ctx, gallery.ResourceGroup, imageName, armcompute.Image{
+			Location: &gallery.Region,
+			Properties: &armcompute.ImageProperties{
+				HyperVGeneration: &gen,
+				StorageProfile: &armcompute.ImageStorageProfile{
+					OSDisk: &armcompute.ImageOSDisk{
+						OSState: ptr.P(armcompute.OperatingSystemStateTypesGeneralized),
+						OSType:  ptr.P(armcompute.OperatingSystemTypesLinux),
+						BlobURI: &blobURL,
+						Caching: ptr.P(armcompute.CachingTypesReadWrite),
+					},
 				},
 			},
-		},
-	}, nil)
+		}, nil)
+	})
 	if err != nil {
 		return "", fmt.Errorf("cannot create or update image %s: %w", imageName, err)
 	}
 
 	var r armcompute.ImagesClientCreateOrUpdateResponse
-	r, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-		Frequency: time.Second * 7,
-	})
+	r, err = pollUntilDone(ctx, PollStepImport, "image "+imageName, poller)
 	if err != nil {
 		return "", fmt.Errorf("cannot create or update image %s: %w", imageName, err)
 	}
@@ -782,8 +1154,8 @@ func (p *azure) createImage(ctx context.Context, gallery *azureGalleryCredential
 	return *r.ID, nil
 }
 
-func (p *azure) createImageVersion(ctx context.Context, gallery *azureGalleryCredentials, imageDefinition, imageVersion, imageID string,
-	regions []string, secureBoot bool, _, kek, db string,
+func (p *azure) createImageVersion(ctx context.Context, gallery *azureGalleryCredentials, imageDefinition, imageVersion string,
+	source *armcompute.GalleryArtifactVersionFullSource, regions []string, secureBoot bool, _, kek, db string,
 ) error {
 	var security *armcompute.ImageVersionSecurityProfile
 	if secureBoot {
@@ -829,33 +1201,33 @@ func (p *azure) createImageVersion(ctx context.Context, gallery *azureGalleryCre
 	ctx = log.WithValues(ctx, "imageDefinition", imageDefinition, "imageVersion", imageVersion)
 
 	log.Info(ctx, "Creating image version")
-	poller, err := p.galleryImageVersionsClient.BeginCreateOrUpdate(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition,
-		imageVersion, armcompute.GalleryImageVersion{
-			Location: &gallery.Region,
-			Properties: &armcompute.GalleryImageVersionProperties{
-				StorageProfile: &armcompute.GalleryImageVersionStorageProfile{
-					Source: &armcompute.GalleryArtifactVersionFullSource{
-						ID: &imageID,
+	poller, err := retried(ctx, "create image version", func(ctx context.Context) (
+		*runtime.Poller[armcompute.GalleryImageVersionsClientCreateOrUpdateResponse], error,
+	) {
+		return p.galleryImageVersionsClient.BeginCreateOrUpdate(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition,
+			imageVersion, armcompute.GalleryImageVersion{
+				Location: &gallery.Region,
+				Properties: &armcompute.GalleryImageVersionProperties{
+					StorageProfile: &armcompute.GalleryImageVersionStorageProfile{
+						Source: source,
+					},
+					PublishingProfile: &armcompute.GalleryImageVersionPublishingProfile{
+						ReplicaCount:       ptr.P(int32(1)),
+						StorageAccountType: ptr.P(armcompute.StorageAccountTypeStandardLRS),
+						TargetRegions:      targetRegions,
 					},
+					SecurityProfile: security,
 				},
-				PublishingProfile: &armcompute.GalleryImageVersionPublishingProfile{
-					ReplicaCount:       ptr.P(int32(1)),
-					StorageAccountType: ptr.P(armcompute.StorageAccountTypeStandardLRS),
-					TargetRegions:      targetRegions,
+				Tags: map[string]*string{
+					"component": ptr.P("gardenlinux"),
 				},
-				SecurityProfile: security,
-			},
-			Tags: map[string]*string{
-				"component": ptr.P("gardenlinux"),
-			},
-		}, nil)
+			}, nil)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot create or update image version: %w", err)
 	}
 
-	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
-		Frequency: time.Second * 7,
-	})
+	_, err = pollUntilDone(ctx, PollStepReplication, "image version "+imageVersion+" of "+imageDefinition, poller)
 	if err != nil {
 		return fmt.Errorf("cannot create or update image version: %w", err)
 	}
@@ -865,7 +1237,9 @@ func (p *azure) createImageVersion(ctx context.Context, gallery *azureGalleryCre
 
 func (p *azure) getPublicID(ctx context.Context, gallery *azureGalleryCredentials, imageDefinition, imageVersion string) (string, error) {
 	log.Debug(ctx, "Getting gallery")
-	gr, err := p.galleriesClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, nil)
+	gr, err := retried(ctx, "get gallery", func(ctx context.Context) (armcompute.GalleriesClientGetResponse, error) {
+		return p.galleriesClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, nil)
+	})
 	if err != nil {
 		return "", fmt.Errorf("cannot get gallery: %w", err)
 	}
@@ -878,7 +1252,11 @@ func (p *azure) getPublicID(ctx context.Context, gallery *azureGalleryCredential
 
 	log.Debug(ctx, "Getting image version")
 	var givr armcompute.CommunityGalleryImageVersionsClientGetResponse
-	givr, err = p.communityGalleryImageVersionsClient.Get(ctx, gallery.Region, publicName, imageDefinition, imageVersion, nil)
+	givr, err = retried(ctx, "get community image version", func(ctx context.Context) (
+		armcompute.CommunityGalleryImageVersionsClientGetResponse, error,
+	) {
+		return p.communityGalleryImageVersionsClient.Get(ctx, gallery.Region, publicName, imageDefinition, imageVersion, nil)
+	})
 	if err != nil {
 		return "", fmt.Errorf("cannot get community gallery image version: %w", err)
 	}
@@ -894,9 +1272,11 @@ func (p *azure) deleteBlob(ctx context.Context, blob string) error {
 	ctx = log.WithValues(ctx, "container", container, "blob", blob)
 
 	log.Info(ctx, "Deleting blob")
-	blobClient := p.storageClient.ServiceClient().NewContainerClient(container).NewPageBlobClient(blob)
-	_, err := blobClient.Delete(ctx, &azblob.DeleteBlobOptions{
-		DeleteSnapshots: ptr.P(azblob.DeleteSnapshotsOptionTypeInclude),
+	blobClient := p.blobClient(blob)
+	_, err := retried(ctx, "delete blob", func(ctx context.Context) (azblob.DeleteBlobResponse, error) {
+		return blobClient.Delete(ctx, &azblob.DeleteBlobOptions{
+			DeleteSnapshots: ptr.P(azblob.DeleteSnapshotsOptionTypeInclude),
+		})
 	})
 	if err != nil {
 		return fmt.Errorf("cannot delete blob %s: %w", blob, err)
@@ -915,7 +1295,9 @@ func (p *azure) unpackPublicID(ctx context.Context, gallery *azureGalleryCredent
 	imageVersion := parts[6]
 
 	log.Debug(ctx, "Getting gallery")
-	r, err := p.galleriesClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, nil)
+	r, err := retried(ctx, "get gallery", func(ctx context.Context) (armcompute.GalleriesClientGetResponse, error) {
+		return p.galleriesClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, nil)
+	})
 	if err != nil {
 		return "", "", fmt.Errorf("cannot get gallery %s: %w", gallery.Gallery, err)
 	}
@@ -939,11 +1321,165 @@ func (p *azure) unpackPublicID(ctx context.Context, gallery *azureGalleryCredent
 	return imageDefinition, imageVersion, nil
 }
 
+func (p *azure) verifyImage(ctx context.Context, gallery *azureGalleryCredentials, img PublishedImage, regions []string,
+) ([]ImageProblem, error) {
+	imageDefinition, imageVersion, err := p.unpackPublicID(ctx, gallery, img.ID)
+	if err != nil {
+		return nil, err
+	}
+	publicName := strings.Split(img.ID, "/")[2]
+	ctx = log.WithValues(ctx, "imageDefinition", imageDefinition, "imageVersion", imageVersion)
+
+	log.Debug(ctx, "Verifying image version")
+	var r armcompute.GalleryImageVersionsClientGetResponse
+	r, err = retried(ctx, "get image version", func(ctx context.Context) (armcompute.GalleryImageVersionsClientGetResponse, error) {
+		return p.galleryImageVersionsClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, imageVersion, nil)
+	})
+	if err != nil {
+		var rerr *azcore.ResponseError
+		if errors.As(err, &rerr) && rerr.StatusCode == http.StatusNotFound {
+			return []ImageProblem{
+				{
+					Image:   img,
+					Problem: ProblemDeleted,
+				},
+			}, nil
+		}
+		return nil, fmt.Errorf("cannot get gallery image version: %w", err)
+	}
+	if r.Properties == nil || r.Properties.ProvisioningState == nil || r.Properties.PublishingProfile == nil {
+		return nil, errors.New("cannot get gallery image version: missing properties")
+	}
+	if *r.Properties.ProvisioningState != armcompute.GalleryProvisioningStateSucceeded {
+		return []ImageProblem{
+			{
+				Image:   img,
+				Problem: ProblemNotAvailable,
+				Detail:  "provisioning state " + string(*r.Properties.ProvisioningState),
+			},
+		}, nil
+	}
+
+	var problems []ImageProblem
+	_, err = retried(ctx, "get community image version", func(ctx context.Context) (
+		armcompute.CommunityGalleryImageVersionsClientGetResponse, error,
+	) {
+		return p.communityGalleryImageVersionsClient.Get(ctx, gallery.Region, publicName, imageDefinition, imageVersion, nil)
+	})
+	if err != nil {
+		var rerr *azcore.ResponseError
+		if !errors.As(err, &rerr) || rerr.StatusCode != http.StatusNotFound {
+			return nil, fmt.Errorf("cannot get community gallery image version: %w", err)
+		}
+		problems = append(problems, ImageProblem{
+			Image:   img,
+			Problem: ProblemPrivate,
+		})
+	}
+
+	found := make(map[string]struct{}, len(r.Properties.PublishingProfile.TargetRegions))
+	for _, region := range r.Properties.PublishingProfile.TargetRegions {
+		if region != nil && region.Name != nil {
+			found[p.normalizeRegion(*region.Name)] = struct{}{}
+		}
+	}
+	for _, region := range regions {
+		_, ok := found[p.normalizeRegion(region)]
+		if !ok {
+			problems = append(problems, ImageProblem{
+				Image: PublishedImage{
+					Cloud:  img.Cloud,
+					Region: region,
+					ID:     img.ID,
+				},
+				Problem: ProblemMissingRegion,
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+// normalizeRegion maps both the name and the display name of a region to the same string.
+// listOrphanedImageVersions returns the image versions created by GLCI that are not referenced as well as the IDs of all images that
+// are used as source by an image version.
+func (p *azure) listOrphanedImageVersions(ctx context.Context, gallery *azureGalleryCredentials, referenced map[string]struct{},
+) ([]Resource, map[string]struct{}, error) {
+	cld := p.cloud()
+	var orphans []Resource
+	inUse := make(map[string]struct{})
+
+	log.Debug(ctx, "Listing image definitions")
+	definitions := p.galleryImagesClient.NewListByGalleryPager(gallery.ResourceGroup, gallery.Gallery, nil)
+	for definitions.More() {
+		page, err := definitions.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot list gallery image definitions: %w", err)
+		}
+
+		for _, definition := range page.Value {
+			if definition.Name == nil {
+				return nil, nil, errors.New("cannot list gallery image definitions: missing name")
+			}
+			lctx := log.WithValues(ctx, "imageDefinition", *definition.Name)
+
+			log.Debug(lctx, "Listing image versions")
+			versions := p.galleryImageVersionsClient.NewListByGalleryImagePager(gallery.ResourceGroup, gallery.Gallery, *definition.Name,
+				nil)
+			for versions.More() {
+				var versionsPage armcompute.GalleryImageVersionsClientListByGalleryImageResponse
+				versionsPage, err = versions.NextPage(lctx)
+				if err != nil {
+					return nil, nil, fmt.Errorf("cannot list gallery image versions of %s: %w", *definition.Name, err)
+				}
+
+				for _, version := range versionsPage.Value {
+					if version.Name == nil {
+						return nil, nil, fmt.Errorf("cannot list gallery image versions of %s: missing name", *definition.Name)
+					}
+					props := version.Properties
+					if props != nil && props.StorageProfile != nil && props.StorageProfile.Source != nil &&
+						props.StorageProfile.Source.ID != nil {
+						inUse[strings.ToLower(*props.StorageProfile.Source.ID)] = struct{}{}
+					}
+
+					if ptr.V(version.Tags["component"]) != "gardenlinux" {
+						continue
+					}
+					_, ok := referenced[strings.ToLower(*definition.Name+"/"+*version.Name)]
+					if ok {
+						continue
+					}
+					var created time.Time
+					if props != nil && props.PublishingProfile != nil {
+						created = ptr.V(props.PublishingProfile.PublishedDate)
+					}
+					orphans = append(orphans, Resource{
+						Kind:    ResourceKindImageVersion,
+						Cloud:   cld,
+						ID:      *definition.Name + "/" + *version.Name,
+						Name:    *version.Name,
+						Created: created,
+					})
+				}
+			}
+		}
+	}
+
+	return orphans, inUse, nil
+}
+
+func (*azure) normalizeRegion(region string) string {
+	return strings.ToLower(strings.ReplaceAll(region, " ", ""))
+}
+
 func (p *azure) deleteImageVersion(ctx context.Context, gallery *azureGalleryCredentials, imageDefinition, imageVersion string) (string,
 	string, error,
 ) {
 	log.Debug(ctx, "Getting gallery image version")
-	r, err := p.galleryImageVersionsClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, imageVersion, nil)
+	r, err := retried(ctx, "get image version", func(ctx context.Context) (armcompute.GalleryImageVersionsClientGetResponse, error) {
+		return p.galleryImageVersionsClient.Get(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, imageVersion, nil)
+	})
 	if err != nil {
 		return "", "", fmt.Errorf("cannot get gallery image version: %w", err)
 	}
@@ -960,7 +1496,11 @@ func (p *azure) deleteImageVersion(ctx context.Context, gallery *azureGalleryCre
 
 	log.Info(ctx, "Deleting image version")
 	var poller *runtime.Poller[armcompute.GalleryImageVersionsClientDeleteResponse]
-	poller, err = p.galleryImageVersionsClient.BeginDelete(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, imageVersion, nil)
+	poller, err = retried(ctx, "delete image version", func(ctx context.Context) (
+		*runtime.Poller[armcompute.GalleryImageVersionsClientDeleteResponse], error,
+	) {
+		return p.galleryImageVersionsClient.BeginDelete(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, imageVersion, nil)
+	})
 	if err != nil {
 		return "", "", fmt.Errorf("cannot delete gallery image version: %w", err)
 	}
@@ -975,9 +1515,26 @@ func (p *azure) deleteImageVersion(ctx context.Context, gallery *azureGalleryCre
 	return imageResourceGroup, image, nil
 }
 
+func (p *azure) trackImage(ctx context.Context, step, imageResourceGroup, image string) {
+	track(ctx, step, "image "+image, func(ctx context.Context) error {
+		return p.deleteImage(ctx, imageResourceGroup, image)
+	})
+}
+
+func (p *azure) trackImageVersion(ctx context.Context, step string, gallery *azureGalleryCredentials, imageDefinition,
+	imageVersion string,
+) {
+	track(ctx, step, "image version "+imageVersion+" of image definition "+imageDefinition, func(ctx context.Context) error {
+		_, _, err := p.deleteImageVersion(ctx, gallery, imageDefinition, imageVersion)
+		return err
+	})
+}
+
 func (p *azure) deleteImage(ctx context.Context, imageResourceGroup, image string) error {
 	log.Info(ctx, "Deleting image")
-	poller, err := p.imagesClient.BeginDelete(ctx, imageResourceGroup, image, nil)
+	poller, err := retried(ctx, "delete image", func(ctx context.Context) (*runtime.Poller[armcompute.ImagesClientDeleteResponse], error) {
+		return p.imagesClient.BeginDelete(ctx, imageResourceGroup, image, nil)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot delete image: %w", err)
 	}
@@ -1003,7 +1560,11 @@ func (p *azure) deleteEmptyImageDefinition(ctx context.Context, gallery *azureGa
 		if len(page.Value) == 0 {
 			log.Info(ctx, "Deleting image definition")
 			var poller *runtime.Poller[armcompute.GalleryImagesClientDeleteResponse]
-			poller, err = p.galleryImagesClient.BeginDelete(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, nil)
+			poller, err = retried(ctx, "delete image definition", func(ctx context.Context) (
+				*runtime.Poller[armcompute.GalleryImagesClientDeleteResponse], error,
+			) {
+				return p.galleryImagesClient.BeginDelete(ctx, gallery.ResourceGroup, gallery.Gallery, imageDefinition, nil)
+			})
 			if err != nil {
 				return fmt.Errorf("cannot delete gallery image definition: %w", err)
 			}
@@ -1019,3 +1580,37 @@ func (p *azure) deleteEmptyImageDefinition(ctx context.Context, gallery *azureGa
 
 	return nil
 }
+
+// pollUntilDone polls a long-running operation of a step according to the poll policy and returns its result.
+func pollUntilDone[T any](ctx context.Context, step PollStep, resource string, poller *runtime.Poller[T]) (T, error) {
+	err := poll(ctx, step, resource, func(ctx context.Context) (bool, string, error) {
+		resp, err := poller.Poll(ctx)
+		if err != nil {
+			return false, "", err //nolint:wrapcheck // Directly wraps the Azure poller.
+		}
+
+		return poller.Done(), operationStatus(resp), nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return poller.Result(ctx) //nolint:wrapcheck // Directly wraps the Azure poller.
+}
+
+// operationStatus returns the status of a long-running operation from a polling response, or the HTTP status if it has none.
+func operationStatus(resp *http.Response) string {
+	body, err := runtime.Payload(resp)
+	if err == nil {
+		var operation struct {
+			Status string `json:"status"`
+		}
+		err = json.Unmarshal(body, &operation)
+		if err == nil && operation.Status != "" {
+			return operation.Status
+		}
+	}
+
+	return resp.Status
+}
@@ -0,0 +1,219 @@
+package cloudprovider
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is only used to verify checksums recorded in manifests.
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// ChecksumError is returned when the content of a release file does not match the checksum recorded in its manifest.
+type ChecksumError struct {
+	Key       string
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e ChecksumError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch for %s: expected %s, got %s", e.Algorithm, e.Key, e.Expected, e.Actual)
+}
+
+// checksumReader verifies the checksums of a release file while it is being read. Instead of io.EOF, it returns a ChecksumError at the
+// end of the file if the content does not match, so that consumers abort rather than finish an upload.
+type checksumReader struct {
+	r      io.Reader
+	file   gl.S3ReleaseFile
+	md5    hash.Hash
+	sha256 hash.Hash
+}
+
+func newChecksumReader(r io.Reader, file gl.S3ReleaseFile) io.Reader {
+	c := &checksumReader{
+		r:    r,
+		file: file,
+	}
+	if file.MD5Sum != nil && *file.MD5Sum != "" {
+		c.md5 = md5.New() //nolint:gosec // MD5 is only used to verify checksums recorded in manifests.
+	}
+	if file.SHA256Sum != nil && *file.SHA256Sum != "" {
+		c.sha256 = sha256.New()
+	}
+	if c.md5 == nil && c.sha256 == nil {
+		return r
+	}
+
+	return c
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		if c.md5 != nil {
+			_, _ = c.md5.Write(p[:n])
+		}
+		if c.sha256 != nil {
+			_, _ = c.sha256.Write(p[:n])
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		verr := c.verify()
+		if verr != nil {
+			return n, verr
+		}
+	}
+
+	return n, err //nolint:wrapcheck // Directly wraps the reader.
+}
+
+func (c *checksumReader) verify() error {
+	if c.sha256 != nil {
+		err := compareChecksum(c.file.S3Key, "SHA256", *c.file.SHA256Sum, c.sha256)
+		if err != nil {
+			return err
+		}
+	}
+	if c.md5 != nil {
+		err := compareChecksum(c.file.S3Key, "MD5", *c.file.MD5Sum, c.md5)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func compareChecksum(key, algorithm, expected string, h hash.Hash) error {
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return ChecksumError{
+			Key:       key,
+			Algorithm: algorithm,
+			Expected:  expected,
+			Actual:    actual,
+		}
+	}
+
+	return nil
+}
+
+// getReleaseFile retrieves a release file from an artifact source, its checksums are verified while it is being read.
+func getReleaseFile(ctx context.Context, source ArtifactSource, file gl.S3ReleaseFile) (io.ReadCloser, error) {
+	body, err := source.GetObject(ctx, file.S3Key)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Directly wraps the source.
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: newChecksumReader(body, file),
+		Closer: body,
+	}, nil
+}
+
+// WithVerifiedFiles stores a record of verified release files into the context, so that each release file is verified only once for all
+// publishing targets importing it during a run.
+func WithVerifiedFiles(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxkVerifiedFiles{}, &verifiedFiles{
+		files: make(map[string]*verifiedFile),
+	})
+}
+
+type ctxkVerifiedFiles struct{}
+
+type verifiedFiles struct {
+	mtx   sync.Mutex
+	files map[string]*verifiedFile
+}
+
+type verifiedFile struct {
+	mtx      sync.Mutex
+	verified bool
+}
+
+// file returns the record of a release file. Its mutex serializes concurrent verifications of the same file.
+func (v *verifiedFiles) file(source ArtifactSource, file gl.S3ReleaseFile) *verifiedFile {
+	key := source.Type() + ":" + source.Repository() + "/" + file.S3Key
+	if file.SHA256Sum != nil {
+		key += "@sha256:" + *file.SHA256Sum
+	}
+	if file.MD5Sum != nil {
+		key += "@md5:" + *file.MD5Sum
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	f, ok := v.files[key]
+	if !ok {
+		f = &verifiedFile{}
+		v.files[key] = f
+	}
+
+	return f
+}
+
+// verifyReleaseFile reads a release file once to verify its checksums. It is used where the cloud pulls the file itself, so that a corrupt
+// file is detected before anything is imported. A release file which has already been verified during the run is not read again.
+func verifyReleaseFile(ctx context.Context, source ArtifactSource, file gl.S3ReleaseFile) error {
+	if (file.MD5Sum == nil || *file.MD5Sum == "") && (file.SHA256Sum == nil || *file.SHA256Sum == "") {
+		log.Debug(ctx, "No checksums to verify", "key", file.S3Key)
+		return nil
+	}
+
+	verified, _ := ctx.Value(ctxkVerifiedFiles{}).(*verifiedFiles)
+	if verified == nil {
+		return readReleaseFile(ctx, source, file)
+	}
+
+	f := verified.file(source, file)
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.verified {
+		log.Debug(ctx, "Checksums already verified", "key", file.S3Key)
+		return nil
+	}
+
+	err := readReleaseFile(ctx, source, file)
+	if err != nil {
+		return err
+	}
+	f.verified = true
+
+	return nil
+}
+
+func readReleaseFile(ctx context.Context, source ArtifactSource, file gl.S3ReleaseFile) error {
+	log.Info(ctx, "Verifying checksums", "key", file.S3Key)
+	body, err := getReleaseFile(ctx, source, file)
+	if err != nil {
+		return fmt.Errorf("cannot get object %s: %w", file.S3Key, err)
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	_, err = io.Copy(io.Discard, body)
+	if err != nil {
+		return fmt.Errorf("cannot verify object %s: %w", file.S3Key, err)
+	}
+
+	err = body.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close object %s: %w", file.S3Key, err)
+	}
+
+	return nil
+}
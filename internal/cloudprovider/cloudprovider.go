@@ -6,11 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/goccy/go-yaml"
 
 	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
 )
 
 // ArtifactSource is a source of artifacts which can retrieve arbitrary objects as well as retrieve and publish manifests.
@@ -24,6 +28,9 @@ type ArtifactSource interface {
 	GetObjectSize(ctx context.Context, key string) (int64, error)
 	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
 	PutObject(ctx context.Context, key string, object io.Reader) error
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	DeleteObject(ctx context.Context, key string) error
+	CopyObject(ctx context.Context, fromKey, toKey string) error
 }
 
 // PublishingTarget is a target onto which GLCI can publish Garden Linux images.
@@ -33,13 +40,28 @@ type PublishingTarget interface {
 	SetTargetConfig(ctx context.Context, credentials map[string]any, sources map[string]ArtifactSource) error
 	Close() error
 	ImageSuffix() string
+	Platforms() []string
+	Clouds() []string
 	IsPublished(manifest *gl.Manifest) (bool, error)
+	PublishedImages(manifest *gl.Manifest) ([]PublishedImage, error)
+	Verify(ctx context.Context, manifest *gl.Manifest) ([]ImageProblem, error)
+	SetFilter(ctx context.Context, filter Filter) (bool, error)
+	Orphans(ctx context.Context, published []PublishedImage) ([]Resource, error)
+	DeleteOrphan(ctx context.Context, resource Resource) error
 	AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error)
 	RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutput, error)
 	Publish(ctx context.Context, cname string, manifest *gl.Manifest, sources map[string]ArtifactSource) (PublishingOutput, error)
 	Remove(ctx context.Context, manifest *gl.Manifest, sources map[string]ArtifactSource) error
 }
 
+// PromotingTarget is a PublishingTarget which can publish an image by reusing the image that another publishing target of the same type
+// has already published for the same flavor, instead of importing it again.
+type PromotingTarget interface {
+	PublishingTarget
+	Promote(ctx context.Context, cname string, manifest *gl.Manifest, from PublishingTarget, fromManifest *gl.Manifest,
+		sources map[string]ArtifactSource) (PublishingOutput, error)
+}
+
 // OCMTarget is a target onto which GLCI can publish an OCM component descriptor.
 type OCMTarget interface {
 	Type() string
@@ -80,8 +102,23 @@ func NewOCMTarget(typ string) (OCMTarget, error) {
 	return nf(), nil
 }
 
-// GetManifest retrieves a manifest from an artifact source.
+// GetManifest retrieves a manifest from an artifact source and migrates it to the current manifest schema in memory.
 func GetManifest(ctx context.Context, source ArtifactSource, key string) (*gl.Manifest, error) {
+	manifest, err := GetStoredManifest(ctx, source, key)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = manifest.Migrate()
+	if err != nil {
+		return nil, fmt.Errorf("cannot migrate manifest %s: %w", key, err)
+	}
+
+	return manifest, nil
+}
+
+// GetStoredManifest retrieves a manifest from an artifact source as it is stored, without migrating it to the current manifest schema.
+func GetStoredManifest(ctx context.Context, source ArtifactSource, key string) (*gl.Manifest, error) {
 	body, err := source.GetObject(ctx, key)
 	if err != nil {
 		return nil, err //nolint:wrapcheck // Directly wraps the source.
@@ -90,8 +127,19 @@ func GetManifest(ctx context.Context, source ArtifactSource, key string) (*gl.Ma
 		_ = body.Close()
 	}()
 
+	var data []byte
+	data, err = io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read object: %w", err)
+	}
+	err = verifyManifest(ctx, source, key, data)
+	unsigned := errors.Is(err, ErrManifestNotSigned)
+	if err != nil && !unsigned {
+		return nil, fmt.Errorf("invalid signature of manifest %s: %w", key, err)
+	}
+
 	var rawManifest map[string]any
-	err = yaml.NewDecoder(body).Decode(&rawManifest)
+	err = yaml.Unmarshal(data, &rawManifest)
 	if err != nil {
 		return nil, fmt.Errorf("invalid manifest: %w", err)
 	}
@@ -109,6 +157,13 @@ func GetManifest(ctx context.Context, source ArtifactSource, key string) (*gl.Ma
 	if err != nil {
 		return nil, fmt.Errorf("invalid manifest: %w", err)
 	}
+	if unsigned && (manifest.SchemaVersion != 0 || !manifest.PublishedImageMetadata.IsEmpty()) {
+		return nil, fmt.Errorf("invalid signature of manifest %s: %w", key, ErrManifestNotSigned)
+	}
+	err = manifest.PublishedImageMetadata.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid published image metadata in manifest: %w", err)
+	}
 
 	err = body.Close()
 	if err != nil {
@@ -118,15 +173,110 @@ func GetManifest(ctx context.Context, source ArtifactSource, key string) (*gl.Ma
 	return manifest, nil
 }
 
-// PutManifest stores a manifest into an ArtifactSource.
+// PutManifest stores a manifest into an ArtifactSource. The manifest is migrated to the current manifest schema first, so that it is
+// stored with the current schema version.
 func PutManifest(ctx context.Context, source ArtifactSource, key string, manifest *gl.Manifest) error {
+	_, err := manifest.Migrate()
+	if err != nil {
+		return fmt.Errorf("cannot migrate manifest: %w", err)
+	}
+	if !manifest.PublishedImageMetadata.IsEmpty() {
+		manifest.PublishedImageMetadata.SchemaVersion = gl.PublishedImageMetadataSchemaVersion
+	}
+	err = manifest.PublishedImageMetadata.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid published image metadata in manifest: %w", err)
+	}
+
 	var buf bytes.Buffer
-	err := yaml.NewEncoder(&buf).Encode(manifest)
+	err = yaml.NewEncoder(&buf).Encode(manifest)
 	if err != nil {
 		return fmt.Errorf("invalid manifest: %w", err)
 	}
+	data := buf.Bytes()
+
+	err = putManifestSignature(ctx, source, key, data)
+	if err != nil {
+		return fmt.Errorf("cannot put signature of manifest %s: %w", key, err)
+	}
+
+	err = source.PutObject(ctx, key, bytes.NewReader(data))
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the source.
+	}
+
+	deleteStaleManifestSignatures(ctx, source, key, data)
+
+	return nil
+}
+
+// DeleteManifest deletes a manifest from an ArtifactSource along with its signatures, if manifests are signed.
+func DeleteManifest(ctx context.Context, source ArtifactSource, key string) error {
+	err := source.DeleteObject(ctx, key)
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the source.
+	}
+
+	signing, ok := manifestSigningOf(source)
+	if !ok || (signing.Signer == nil && len(signing.TrustedKeys) == 0) {
+		return nil
+	}
+	var sigKeys []string
+	sigKeys, err = listManifestSignatures(ctx, source, key)
+	if err != nil {
+		return fmt.Errorf("invalid signatures of manifest %s: %w", key, err)
+	}
+	for _, sigKey := range sigKeys {
+		err = source.DeleteObject(ctx, sigKey)
+		if err != nil && !errors.As(err, &KeyNotFoundError{}) {
+			return fmt.Errorf("cannot delete signature of manifest %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// CopyManifest copies a manifest within an ArtifactSource. Since signatures are bound to the key of a manifest, the copy is signed anew
+// with the content whose signature has been verified, if manifests are signed.
+func CopyManifest(ctx context.Context, source ArtifactSource, key, newKey string) error {
+	signing, ok := manifestSigningOf(source)
+	if !ok || signing.Signer == nil {
+		//nolint:wrapcheck // Directly wraps the source.
+		return source.CopyObject(ctx, key, newKey)
+	}
+
+	body, err := source.GetObject(ctx, key)
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the source.
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var manifest []byte
+	manifest, err = io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("cannot read manifest %s: %w", key, err)
+	}
+
+	err = body.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close object: %w", err)
+	}
 
-	return source.PutObject(ctx, key, &buf) //nolint:wrapcheck // Directly wraps the source.
+	err = verifyManifest(ctx, source, key, manifest)
+	if err != nil && !errors.Is(err, ErrManifestNotSigned) {
+		return fmt.Errorf("invalid signature of manifest %s: %w", key, err)
+	}
+	if err == nil {
+		err = putManifestSignature(ctx, source, newKey, manifest)
+		if err != nil {
+			return fmt.Errorf("cannot sign manifest %s: %w", newKey, err)
+		}
+	}
+
+	//nolint:wrapcheck // Directly wraps the source.
+	return source.PutObject(ctx, newKey, bytes.NewReader(manifest))
 }
 
 // Publication represents the act of publishing an image including what is being published where and what the result is.
@@ -136,8 +286,78 @@ type Publication struct {
 	Target   PublishingTarget
 }
 
-// PublishingOutput is an opaque representation of the result of a publishing operation.
-type PublishingOutput any
+// PublishedImage describes an image found in the publishing output of a publishing target.
+type PublishedImage struct {
+	// Cloud is the cloud, project or hypervisor of the image if the publishing target distinguishes them.
+	Cloud  string `json:"cloud,omitempty"  yaml:"cloud,omitempty"`
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+	ID     string `json:"id"               yaml:"id"`
+	Name   string `json:"name,omitempty"   yaml:"name,omitempty"`
+}
+
+// ImageProblem describes a discrepancy between an image recorded in a manifest and what actually exists on the publishing target.
+type ImageProblem struct {
+	Image   PublishedImage `json:"image"            yaml:"image"`
+	Problem Problem        `json:"problem"          yaml:"problem"`
+	Detail  string         `json:"detail,omitempty" yaml:"detail,omitempty"`
+}
+
+// Problem is the kind of discrepancy found for an image.
+type Problem string
+
+const (
+	// ProblemDeleted means that the image no longer exists.
+	ProblemDeleted Problem = "deleted"
+	// ProblemNotAvailable means that the image exists but is not in an available state.
+	ProblemNotAvailable Problem = "not available"
+	// ProblemPrivate means that the image exists but is not public.
+	ProblemPrivate Problem = "private"
+	// ProblemMissingRegion means that no image has been published to a region which the publishing target is configured for.
+	ProblemMissingRegion Problem = "missing region"
+)
+
+// ObjectInfo describes an object stored in an artifact source.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	ETag         string
+}
+
+// Resource describes a resource created by GLCI on a publishing target, such as an image, a snapshot or a staging blob.
+type Resource struct {
+	Kind ResourceKind `json:"kind"             yaml:"kind"`
+	// Cloud is the cloud, project or hypervisor of the resource if the publishing target distinguishes them.
+	Cloud   string    `json:"cloud,omitempty"  yaml:"cloud,omitempty"`
+	Region  string    `json:"region,omitempty" yaml:"region,omitempty"`
+	ID      string    `json:"id"               yaml:"id"`
+	Name    string    `json:"name,omitempty"   yaml:"name,omitempty"`
+	Created time.Time `json:"created"          yaml:"created"`
+}
+
+// ResourceKind is the kind of a resource created by GLCI.
+type ResourceKind string
+
+const (
+	// ResourceKindImage is a bootable image.
+	ResourceKindImage ResourceKind = "image"
+	// ResourceKindImageVersion is a version of an image definition in an image gallery.
+	ResourceKindImageVersion ResourceKind = "image version"
+	// ResourceKindSnapshot is a disk snapshot from which an image is registered.
+	ResourceKindSnapshot ResourceKind = "snapshot"
+	// ResourceKindBlob is a staging object uploaded to a storage bucket or container in order to import an image.
+	ResourceKindBlob ResourceKind = "blob"
+)
+
+// Filter narrows the clouds and regions a publishing target operates on. An empty list does not narrow anything.
+type Filter struct {
+	Clouds  []string
+	Regions []string
+}
+
+// PublishingOutput is the published image metadata resulting from a publishing operation. Each publishing target only fills in and
+// modifies the sections of its own platform.
+type PublishingOutput = *gl.PublishedImageMetadata
 
 // KeyNotFoundError wraps a source-specific error inficating that a given key is not present.
 type KeyNotFoundError struct {
@@ -211,10 +431,10 @@ func setConfig[CONFIG any](cfg map[string]any, config *CONFIG) error {
 	return nil
 }
 
-func getObjectBytes(ctx context.Context, source ArtifactSource, key string) ([]byte, error) {
-	body, err := source.GetObject(ctx, key)
+func getObjectBytes(ctx context.Context, source ArtifactSource, file gl.S3ReleaseFile) ([]byte, error) {
+	body, err := getReleaseFile(ctx, source, file)
 	if err != nil {
-		return nil, err //nolint:wrapcheck // Directly wraps the source.
+		return nil, err
 	}
 	defer func() {
 		_ = body.Close()
@@ -234,29 +454,83 @@ func getObjectBytes(ctx context.Context, source ArtifactSource, key string) ([]b
 	return buf.Bytes(), nil
 }
 
-func publishingOutput[PUBOUT any](generic PublishingOutput) (PUBOUT, error) {
-	var output PUBOUT
+// publishedImageMetadata returns a copy of a publishing output which can be modified section by section, it is empty if there is no output.
+func publishedImageMetadata(output PublishingOutput) gl.PublishedImageMetadata {
+	if output == nil {
+		return gl.PublishedImageMetadata{}
+	}
 
-	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-		Result:  &output,
-		TagName: "yaml",
+	return *output
+}
+
+// remainingPublishingOutput returns the publishing output left after a publishing target has removed its own sections, or nil if nothing
+// is left.
+func remainingPublishingOutput(output gl.PublishedImageMetadata) PublishingOutput {
+	if output.IsEmpty() {
+		return nil
+	}
+
+	return &output
+}
+
+func (f Filter) matchesCloud(cloud string) bool {
+	return len(f.Clouds) == 0 || slices.ContainsFunc(f.Clouds, func(c string) bool {
+		return strings.EqualFold(c, cloud)
 	})
-	if err != nil {
-		return output, fmt.Errorf("invalid publishing output: %w", err)
+}
+
+func (f Filter) matchesRegion(region string) bool {
+	return len(f.Regions) == 0 || slices.ContainsFunc(f.Regions, func(r string) bool {
+		return strings.EqualFold(r, region)
+	})
+}
+
+func (f Filter) matchesAnyRegion(regions []string) bool {
+	return slices.ContainsFunc(regions, f.matchesRegion)
+}
+
+// selectRegions returns the regions matching the filter.
+func (f Filter) selectRegions(regions []string) []string {
+	return slices.DeleteFunc(slices.Clone(regions), func(region string) bool {
+		return !f.matchesRegion(region)
+	})
+}
+
+// narrowRegions returns the regions matching the filter, always retaining the required region (if any) since images are imported there.
+func (f Filter) narrowRegions(ctx context.Context, regions []string, required string) []string {
+	if len(f.Regions) == 0 {
+		return regions
 	}
-	err = decoder.Decode(generic)
-	if err != nil {
-		return output, fmt.Errorf("invalid publishing output: %w", err)
+
+	narrowed := make([]string, 0, len(regions))
+	var excluded []string
+	for _, region := range regions {
+		if region == required || f.matchesRegion(region) {
+			narrowed = append(narrowed, region)
+		} else {
+			excluded = append(excluded, region)
+		}
+	}
+	if len(excluded) > 0 {
+		log.Info(ctx, "Excluding regions", "regions", excluded)
 	}
 
-	return output, nil
+	return narrowed
 }
 
-func publishingOutputFromManifest[PUBOUT any](manifest *gl.Manifest) (PUBOUT, error) {
-	output, err := publishingOutput[PUBOUT](manifest.PublishedImageMetadata)
-	if err != nil {
-		return output, fmt.Errorf("invalid published image metadata in manifest: %w", err)
+// unpublishedRegions returns the regions which an image has not been published to yet, since an earlier publication may have been
+// restricted to some regions by a filter. Published images are given by region.
+func unpublishedRegions(ctx context.Context, regions []string, published map[string]string) []string {
+	unpublished := make([]string, 0, len(regions))
+	for _, region := range regions {
+		_, ok := published[region]
+		if !ok {
+			unpublished = append(unpublished, region)
+		}
+	}
+	if len(unpublished) < len(regions) {
+		log.Info(ctx, "Skipping already published regions", "count", len(regions)-len(unpublished))
 	}
 
-	return output, nil
+	return unpublished
 }
@@ -0,0 +1,8 @@
+package cloudprovider
+
+// NewKeyNotFoundError allows tests to report missing objects like the artifact sources do.
+func NewKeyNotFoundError(err error) error {
+	return KeyNotFoundError{
+		err: err,
+	}
+}
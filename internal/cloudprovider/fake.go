@@ -67,14 +67,55 @@ func (*fake) PutObject(_ context.Context, _ string, _ io.Reader) error {
 	return nil
 }
 
+func (*fake) ListObjects(_ context.Context, _ string) ([]ObjectInfo, error) {
+	return nil, nil
+}
+
+func (*fake) DeleteObject(_ context.Context, _ string) error {
+	return nil
+}
+
+func (*fake) CopyObject(_ context.Context, _, _ string) error {
+	return nil
+}
+
 func (*fake) ImageSuffix() string {
 	return ".fake"
 }
 
+// Platforms returns nil since the fake target accepts flavors of any platform.
+func (*fake) Platforms() []string {
+	return nil
+}
+
+func (*fake) Clouds() []string {
+	return nil
+}
+
 func (*fake) IsPublished(_ *gl.Manifest) (bool, error) {
 	return false, nil
 }
 
+func (*fake) PublishedImages(_ *gl.Manifest) ([]PublishedImage, error) {
+	return nil, nil
+}
+
+func (*fake) Verify(_ context.Context, _ *gl.Manifest) ([]ImageProblem, error) {
+	return nil, nil
+}
+
+func (*fake) SetFilter(_ context.Context, _ Filter) (bool, error) {
+	return true, nil
+}
+
+func (*fake) Orphans(_ context.Context, _ []PublishedImage) ([]Resource, error) {
+	return nil, nil
+}
+
+func (*fake) DeleteOrphan(_ context.Context, _ Resource) error {
+	return nil
+}
+
 func (*fake) AddOwnPublishingOutput(output, _ PublishingOutput) (PublishingOutput, error) {
 	return output, nil
 }
@@ -83,8 +124,8 @@ func (*fake) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutpu
 	return output, nil
 }
 
-func (p *fake) Publish(_ context.Context, _ string, _ *gl.Manifest, _ map[string]ArtifactSource) (PublishingOutput, error) {
-	return p, nil
+func (*fake) Publish(_ context.Context, _ string, _ *gl.Manifest, _ map[string]ArtifactSource) (PublishingOutput, error) {
+	return &gl.PublishedImageMetadata{}, nil
 }
 
 func (*fake) Remove(_ context.Context, _ *gl.Manifest, _ map[string]ArtifactSource) error {
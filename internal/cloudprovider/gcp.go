@@ -8,12 +8,18 @@ import (
 	"fmt"
 	"hash/fnv"
 	"io"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	computev1 "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 	"cloud.google.com/go/storage"
+	"github.com/google/uuid"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 
 	"github.com/gardenlinux/glci/internal/env"
@@ -114,39 +120,63 @@ func (*gcp) ImageSuffix() string {
 	return ".gcpimage.tar.gz"
 }
 
+func (*gcp) Platforms() []string {
+	return []string{"gcp"}
+}
+
+func (p *gcp) Clouds() []string {
+	project := p.creds[p.pubCfg.Config].Project
+	if project == "" {
+		return nil
+	}
+
+	return []string{project}
+}
+
 func (p *gcp) IsPublished(manifest *gl.Manifest) (bool, error) {
 	if !p.isConfigured() {
 		return false, errors.New("config not set")
 	}
 
-	gcpOutput, err := publishingOutputFromManifest[gcpPublishingOutput](manifest)
-	if err != nil {
-		return false, err
-	}
+	gcpOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
 
-	return gcpOutput.Project != nil && *gcpOutput.Project != "" && gcpOutput.Image != nil && *gcpOutput.Image != "", nil
+	return gcpOutput.GCPProject != "" && gcpOutput.GCPImage != "", nil
 }
 
-func (p *gcp) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
+func (p *gcp) PublishedImages(manifest *gl.Manifest) ([]PublishedImage, error) {
 	if !p.isConfigured() {
 		return nil, errors.New("config not set")
 	}
 
-	gcpOutput, err := publishingOutput[gcpPublishingOutput](output)
-	if err != nil {
-		return nil, err
+	gcpOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
+	if gcpOutput.GCPProject == "" || gcpOutput.GCPImage == "" {
+		return nil, nil
 	}
-	var ownOutput gcpPublishingOutput
-	ownOutput, err = publishingOutput[gcpPublishingOutput](own)
-	if err != nil {
-		return nil, err
+
+	return []PublishedImage{
+		{
+			Cloud: gcpOutput.GCPProject,
+			ID:    gcpOutput.GCPImage,
+			Name:  gcpOutput.GCPImage,
+		},
+	}, nil
+}
+
+func (p *gcp) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
 
-	if (gcpOutput.Project != nil && *gcpOutput.Project != "") || (gcpOutput.Image != nil && *gcpOutput.Image != "") {
+	gcpOutput := publishedImageMetadata(output)
+	ownOutput := publishedImageMetadata(own)
+
+	if gcpOutput.GCPProject != "" || gcpOutput.GCPImage != "" {
 		return nil, errors.New("cannot add publishing output to existing publishing output")
 	}
 
-	return &ownOutput, nil
+	gcpOutput.GCPProject = ownOutput.GCPProject
+	gcpOutput.GCPImage = ownOutput.GCPImage
+	return &gcpOutput, nil
 }
 
 func (p *gcp) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutput, error) {
@@ -154,12 +184,11 @@ func (p *gcp) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutp
 		return nil, errors.New("config not set")
 	}
 
-	_, err := publishingOutput[gcpPublishingOutput](output)
-	if err != nil {
-		return nil, err
-	}
+	gcpOutput := publishedImageMetadata(output)
+	gcpOutput.GCPProject = ""
+	gcpOutput.GCPImage = ""
 
-	return nil, nil
+	return remainingPublishingOutput(gcpOutput), nil
 }
 
 func (p *gcp) Publish(ctx context.Context, cname string, manifest *gl.Manifest, sources map[string]ArtifactSource) (PublishingOutput,
@@ -193,52 +222,302 @@ func (p *gcp) Publish(ctx context.Context, cname string, manifest *gl.Manifest,
 	}
 	ctx = log.WithValues(ctx, "secureBoot", secureBoot)
 
-	var blob *storage.ObjectHandle
-	var blobURL string
-	blob, blobURL, err = p.uploadBlob(ctx, source, imagePath.S3Key, image)
+	var blobName string
+	blobName, err = journaled(ctx, "blob", func() (string, error) {
+		return p.uploadBlob(ctx, source, imagePath, image)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot upload blob for image %s in project %s: %w", image, project, err)
 	}
+	blob := p.storageClient.Bucket(p.pubCfg.Bucket).Object(blobName)
+	track(ctx, "blob", "blob "+blobName, func(ctx context.Context) error {
+		return p.deleteBlob(ctx, blob)
+	})
+
+	_, err = journaled(ctx, "image", func() (string, error) {
+		blobURL, err := p.signedURL(blobName)
+		if err != nil {
+			return "", err
+		}
 
-	err = p.insertImage(ctx, blobURL, image, arch, secureBoot, pk, kek, db)
+		return image, p.insertImage(ctx, blobURL, "", image, arch, secureBoot, pk, kek, db)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("cannot insert image %s from blob %s in project %s: %w", image, blob.ObjectName(), project, err)
+		return nil, fmt.Errorf("cannot insert image %s from blob %s in project %s: %w", image, blobName, project, err)
 	}
+	track(ctx, "image", "image "+image, func(ctx context.Context) error {
+		return p.deleteImage(ctx, image)
+	})
 
-	err = p.deleteBlob(ctx, blob)
+	_, err = journaled(ctx, "blob_deleted", func() (string, error) {
+		return blobName, p.deleteBlob(ctx, blob)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("cannot delete blob %s in project %s: %w", blob.ObjectName(), project, err)
+		return nil, fmt.Errorf("cannot delete blob %s in project %s: %w", blobName, project, err)
 	}
+	untrack(ctx, "blob")
 
 	err = p.makePublic(ctx, image)
 	if err != nil {
 		return nil, fmt.Errorf("cannot make image %s public in project %s: %w", image, project, err)
 	}
 
-	return &gcpPublishingOutput{
-		Project: &project,
-		Image:   &image,
+	return &gl.PublishedImageMetadata{
+		GCPProject: project,
+		GCPImage:   image,
 	}, nil
 }
 
-func (p *gcp) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]ArtifactSource) error {
+func (p *gcp) Promote(ctx context.Context, cname string, manifest *gl.Manifest, from PublishingTarget, fromManifest *gl.Manifest,
+	sources map[string]ArtifactSource,
+) (PublishingOutput, error) {
 	if !p.isConfigured() {
-		return errors.New("config not set")
+		return nil, errors.New("config not set")
 	}
 	ctx = log.WithValues(ctx, "target", p.Type())
 
-	pubOut, err := publishingOutputFromManifest[gcpPublishingOutput](manifest)
+	fromGCP, ok := from.(*gcp)
+	if !ok {
+		return nil, fmt.Errorf("cannot promote from %s", from.Type())
+	}
+	fromImages, err := fromGCP.PublishedImages(fromManifest)
 	if err != nil {
-		return fmt.Errorf("invalid manifest: %w", err)
+		return nil, fmt.Errorf("invalid source manifest %s: %w", cname, err)
+	}
+	if len(fromImages) != 1 {
+		return nil, fmt.Errorf("missing source image for %s", cname)
+	}
+	sourceImage := fmt.Sprintf("projects/%s/global/images/%s", fromImages[0].Cloud, fromImages[0].ID)
+
+	image := p.imageName(cname, manifest.Version, manifest.BuildCommittish)
+	var arch string
+	arch, err = p.architecture(manifest.Architecture)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", cname, err)
+	}
+	source := sources[p.pubCfg.Source]
+	project := p.creds[p.pubCfg.Config].Project
+	ctx = log.WithValues(ctx, "image", image, "architecture", arch, "sourceImage", sourceImage, "project", project)
+
+	var secureBoot bool
+	var pk, kek, db string
+	secureBoot, pk, kek, db, err = p.prepareSecureBoot(ctx, source, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prepare secureboot: %w", err)
+	}
+	ctx = log.WithValues(ctx, "secureBoot", secureBoot)
+
+	_, err = journaled(ctx, "image", func() (string, error) {
+		return image, p.insertImage(ctx, "", sourceImage, image, arch, secureBoot, pk, kek, db)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot insert image %s from source image %s in project %s: %w", image, sourceImage, project, err)
+	}
+	track(ctx, "image", "image "+image, func(ctx context.Context) error {
+		return p.deleteImage(ctx, image)
+	})
+
+	err = p.makePublic(ctx, image)
+	if err != nil {
+		return nil, fmt.Errorf("cannot make image %s public in project %s: %w", image, project, err)
+	}
+
+	return &gl.PublishedImageMetadata{
+		GCPProject: project,
+		GCPImage:   image,
+	}, nil
+}
+
+func (p *gcp) Verify(ctx context.Context, manifest *gl.Manifest) ([]ImageProblem, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	images, err := p.PublishedImages(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	var problems []ImageProblem
+	for _, img := range images {
+		lctx := log.WithValues(ctx, "project", img.Cloud, "image", img.ID)
+
+		log.Debug(lctx, "Verifying image")
+		var image *computepb.Image
+		image, err = retried(lctx, "get image", func(ctx context.Context) (*computepb.Image, error) {
+			return p.imagesClient.Get(ctx, &computepb.GetImageRequest{
+				Image:   img.ID,
+				Project: img.Cloud,
+			}, withoutGAXRetries())
+		})
+		if err != nil {
+			var gerr *googleapi.Error
+			if errors.As(err, &gerr) && gerr.Code == http.StatusNotFound {
+				problems = append(problems, ImageProblem{
+					Image:   img,
+					Problem: ProblemDeleted,
+				})
+				continue
+			}
+			return nil, fmt.Errorf("cannot get image %s in project %s: %w", img.ID, img.Cloud, err)
+		}
+		if image.GetStatus() != "READY" {
+			problems = append(problems, ImageProblem{
+				Image:   img,
+				Problem: ProblemNotAvailable,
+				Detail:  "status " + image.GetStatus(),
+			})
+			continue
+		}
+
+		var public bool
+		public, err = p.isPublic(lctx, img.Cloud, img.ID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get IAM policy of image %s in project %s: %w", img.ID, img.Cloud, err)
+		}
+		if !public {
+			problems = append(problems, ImageProblem{
+				Image:   img,
+				Problem: ProblemPrivate,
+			})
+		}
 	}
-	if pubOut.Project == nil || pubOut.Image == nil {
+
+	return problems, nil
+}
+
+func (p *gcp) SetFilter(ctx context.Context, filter Filter) (bool, error) {
+	if !p.isConfigured() {
+		return false, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type(), "project", p.creds[p.pubCfg.Config].Project)
+
+	if !filter.matchesCloud(p.creds[p.pubCfg.Config].Project) {
+		log.Info(ctx, "Excluding project")
+		return false, nil
+	}
+	if len(filter.Regions) > 0 {
+		log.Info(ctx, "Excluding project, images are global and cannot be selected by region")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (p *gcp) Orphans(ctx context.Context, published []PublishedImage) ([]Resource, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
+	}
+	project := p.creds[p.pubCfg.Config].Project
+	ctx = log.WithValues(ctx, "target", p.Type(), "project", project)
+
+	referenced := make(map[string]struct{}, len(published))
+	for _, img := range published {
+		if img.Cloud == project {
+			referenced[img.ID] = struct{}{}
+		}
+	}
+
+	var orphans []Resource
+	log.Debug(ctx, "Listing images")
+	images := p.imagesClient.List(ctx, &computepb.ListImagesRequest{
+		Filter:  ptr.P("name eq gardenlinux-.*"),
+		Project: project,
+	})
+	for {
+		image, err := images.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot list images in project %s: %w", project, err)
+		}
+
+		_, ok := referenced[image.GetName()]
+		if ok {
+			continue
+		}
+		var created time.Time
+		created, err = time.Parse(time.RFC3339, image.GetCreationTimestamp())
+		if err != nil {
+			return nil, fmt.Errorf("invalid creation timestamp of image %s: %w", image.GetName(), err)
+		}
+		orphans = append(orphans, Resource{
+			Kind:    ResourceKindImage,
+			Cloud:   project,
+			ID:      image.GetName(),
+			Name:    image.GetName(),
+			Created: created,
+		})
+	}
+
+	// Blobs are only needed while an image is being created, any remaining blob is a leftover.
+	log.Debug(ctx, "Listing blobs", "bucket", p.pubCfg.Bucket)
+	blobs := p.storageClient.Bucket(p.pubCfg.Bucket).Objects(ctx, &storage.Query{
+		Prefix: "gardenlinux-",
+	})
+	for {
+		attrs, err := blobs.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot list blobs in bucket %s: %w", p.pubCfg.Bucket, err)
+		}
+
+		if !strings.HasSuffix(attrs.Name, ".tar.gz") {
+			continue
+		}
+		orphans = append(orphans, Resource{
+			Kind:    ResourceKindBlob,
+			Cloud:   project,
+			ID:      attrs.Name,
+			Name:    attrs.Name,
+			Created: attrs.Created,
+		})
+	}
+
+	return orphans, nil
+}
+
+func (p *gcp) DeleteOrphan(ctx context.Context, resource Resource) error {
+	if !p.isConfigured() {
+		return errors.New("config not set")
+	}
+	project := p.creds[p.pubCfg.Config].Project
+	ctx = log.WithValues(ctx, "target", p.Type(), "project", project)
+
+	if resource.Cloud != project {
+		return fmt.Errorf("unknown project %s", resource.Cloud)
+	}
+
+	switch resource.Kind {
+	case ResourceKindImage:
+		return p.deleteImage(log.WithValues(ctx, "image", resource.ID), resource.ID)
+	case ResourceKindBlob:
+		return p.deleteBlob(log.WithValues(ctx, "bucket", p.pubCfg.Bucket, "blob", resource.ID),
+			p.storageClient.Bucket(p.pubCfg.Bucket).Object(resource.ID))
+	default:
+		return fmt.Errorf("unsupported resource kind %s", resource.Kind)
+	}
+}
+
+func (p *gcp) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]ArtifactSource) error {
+	if !p.isConfigured() {
+		return errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	pubOut := publishedImageMetadata(manifest.PublishedImageMetadata)
+	if pubOut.GCPProject == "" || pubOut.GCPImage == "" {
 		return errors.New("invalid manifest: missing published images")
 	}
-	ctx = log.WithValues(ctx, "image", *pubOut.Image, "project", *pubOut.Project)
+	ctx = log.WithValues(ctx, "image", pubOut.GCPImage, "project", pubOut.GCPProject)
 
-	err = p.deleteImage(ctx, *pubOut.Image)
+	err := p.deleteImage(ctx, pubOut.GCPImage)
 	if err != nil {
-		return fmt.Errorf("cannot delete image %s in project %s: %w", *pubOut.Image, *pubOut.Project, err)
+		return fmt.Errorf("cannot delete image %s in project %s: %w", pubOut.GCPImage, pubOut.GCPProject, err)
 	}
 
 	return nil
@@ -263,11 +542,6 @@ type gcpPublishingConfig struct {
 	Bucket string `mapstructure:"bucket"`
 }
 
-type gcpPublishingOutput struct {
-	Project *string `yaml:"gcp_project_name,omitempty"`
-	Image   *string `yaml:"gcp_image_name,omitempty"`
-}
-
 func (p *gcp) isConfigured() bool {
 	return p.storageClient != nil && p.imagesClient != nil
 }
@@ -300,7 +574,7 @@ func (*gcp) prepareSecureBoot(ctx context.Context, source ArtifactSource, manife
 		}
 
 		var rawPK []byte
-		rawPK, err = getObjectBytes(ctx, source, pkFile.S3Key)
+		rawPK, err = getObjectBytes(ctx, source, pkFile)
 		if err != nil {
 			return false, "", "", "", fmt.Errorf("cannot get PK: %w", err)
 		}
@@ -313,7 +587,7 @@ func (*gcp) prepareSecureBoot(ctx context.Context, source ArtifactSource, manife
 		}
 
 		var rawKEK []byte
-		rawKEK, err = getObjectBytes(ctx, source, kekFile.S3Key)
+		rawKEK, err = getObjectBytes(ctx, source, kekFile)
 		if err != nil {
 			return false, "", "", "", fmt.Errorf("cannot get KEK: %w", err)
 		}
@@ -326,7 +600,7 @@ func (*gcp) prepareSecureBoot(ctx context.Context, source ArtifactSource, manife
 		}
 
 		var rawDB []byte
-		rawDB, err = getObjectBytes(ctx, source, dbFile.S3Key)
+		rawDB, err = getObjectBytes(ctx, source, dbFile)
 		if err != nil {
 			return false, "", "", "", fmt.Errorf("cannot get DB: %w", err)
 		}
@@ -336,51 +610,57 @@ func (*gcp) prepareSecureBoot(ctx context.Context, source ArtifactSource, manife
 	return secureBoot, pk, kek, db, nil
 }
 
-func (p *gcp) uploadBlob(ctx context.Context, source ArtifactSource, key, image string) (*storage.ObjectHandle, string, error) {
+func (p *gcp) uploadBlob(ctx context.Context, source ArtifactSource, file gl.S3ReleaseFile, image string) (string, error) {
 	blobName := image + ".tar.gz"
-	ctx = log.WithValues(ctx, "bucket", p.pubCfg.Bucket, "key", key, "blob", blobName)
+	ctx = log.WithValues(ctx, "bucket", p.pubCfg.Bucket, "key", file.S3Key, "blob", blobName)
 
-	obj, err := source.GetObject(ctx, key)
+	obj, err := getReleaseFile(ctx, source, file)
 	if err != nil {
-		return nil, "", fmt.Errorf("cannot get blob: %w", err)
+		return "", fmt.Errorf("cannot get blob: %w", err)
 	}
 	defer func() {
 		_ = obj.Close()
 	}()
 
 	log.Info(ctx, "Uploading blob")
-	bucket := p.storageClient.Bucket(p.pubCfg.Bucket)
-	blob := bucket.Object(blobName)
-	w := blob.NewWriter(ctx)
+	// The object is only created when the writer is closed, cancelling the upload discards it.
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := p.storageClient.Bucket(p.pubCfg.Bucket).Object(blobName).NewWriter(wctx)
 	_, err = io.Copy(w, obj)
 	if err != nil {
-		return nil, "", fmt.Errorf("cannot write to object writer: %w", err)
+		cancel()
+		return "", fmt.Errorf("cannot write to object writer: %w", err)
 	}
 	err = w.Close()
 	if err != nil {
-		return nil, "", fmt.Errorf("cannot close object writer: %w", err)
+		return "", fmt.Errorf("cannot close object writer: %w", err)
 	}
 	log.Debug(ctx, "Blob uploaded")
 
 	err = obj.Close()
 	if err != nil {
-		return nil, "", fmt.Errorf("cannot close blob: %w", err)
+		return "", fmt.Errorf("cannot close blob: %w", err)
 	}
 
-	var url string
-	url, err = bucket.SignedURL(blobName, &storage.SignedURLOptions{
+	return blobName, nil
+}
+
+func (p *gcp) signedURL(blobName string) (string, error) {
+	url, err := p.storageClient.Bucket(p.pubCfg.Bucket).SignedURL(blobName, &storage.SignedURLOptions{
 		Method:  "GET",
 		Expires: time.Now().Add(7 * time.Hour),
 		Scheme:  storage.SigningSchemeV4,
 	})
 	if err != nil {
-		return nil, "", fmt.Errorf("cannot generate signed URL for blob %s: %w", blobName, err)
+		return "", fmt.Errorf("cannot generate signed URL for blob %s: %w", blobName, err)
 	}
 
-	return blob, url, nil
+	return url, nil
 }
 
-func (p *gcp) insertImage(ctx context.Context, disk, image, arch string, secureBoot bool, pk, kek, db string) error {
+// insertImage inserts an image either from the URL of a raw disk or from a source image.
+func (p *gcp) insertImage(ctx context.Context, disk, sourceImage, image, arch string, secureBoot bool, pk, kek, db string) error {
 	project := p.creds[p.pubCfg.Config].Project
 	imageResource := &computepb.Image{
 		Architecture: &arch,
@@ -396,9 +676,13 @@ func (p *gcp) insertImage(ctx context.Context, disk, image, arch string, secureB
 			},
 		},
 		Name: &image,
-		RawDisk: &computepb.RawDisk{
+	}
+	if sourceImage != "" {
+		imageResource.SourceImage = &sourceImage
+	} else {
+		imageResource.RawDisk = &computepb.RawDisk{
 			Source: &disk,
-		},
+		}
 	}
 	if secureBoot {
 		imageResource.ShieldedInstanceInitialState = &computepb.InitialStateConfig{
@@ -422,9 +706,13 @@ func (p *gcp) insertImage(ctx context.Context, disk, image, arch string, secureB
 	}
 
 	log.Info(ctx, "Inserting image")
-	op, err := p.imagesClient.Insert(ctx, &computepb.InsertImageRequest{
-		ImageResource: imageResource,
-		Project:       project,
+	requestID := uuid.NewString()
+	op, err := retried(ctx, "insert image", func(ctx context.Context) (*computev1.Operation, error) {
+		return p.imagesClient.Insert(ctx, &computepb.InsertImageRequest{
+			ImageResource: imageResource,
+			Project:       project,
+			RequestId:     &requestID,
+		})
 	})
 	if err != nil {
 		return fmt.Errorf("cannot insert image: %w", err)
@@ -441,7 +729,7 @@ func (p *gcp) insertImage(ctx context.Context, disk, image, arch string, secureB
 
 func (*gcp) deleteBlob(ctx context.Context, blob *storage.ObjectHandle) error {
 	log.Info(ctx, "Deleting blob")
-	err := blob.Delete(ctx)
+	err := retriedErr(ctx, "delete blob", blob.Retryer(storage.WithPolicy(storage.RetryNever)).Delete)
 	if err != nil {
 		return fmt.Errorf("cannot delete blob %s: %w", blob.ObjectName(), err)
 	}
@@ -453,23 +741,25 @@ func (p *gcp) makePublic(ctx context.Context, image string) error {
 	project := p.creds[p.pubCfg.Config].Project
 
 	log.Debug(ctx, "Setting IAM policy")
-	_, err := p.imagesClient.SetIamPolicy(ctx, &computepb.SetIamPolicyImageRequest{
-		GlobalSetPolicyRequestResource: &computepb.GlobalSetPolicyRequest{
-			Policy: &computepb.Policy{
-				AuditConfigs: nil,
-				Bindings: []*computepb.Binding{
-					{
-						Members: []string{
-							"allAuthenticatedUsers",
+	_, err := retried(ctx, "set IAM policy", func(ctx context.Context) (*computepb.Policy, error) {
+		return p.imagesClient.SetIamPolicy(ctx, &computepb.SetIamPolicyImageRequest{
+			GlobalSetPolicyRequestResource: &computepb.GlobalSetPolicyRequest{
+				Policy: &computepb.Policy{
+					AuditConfigs: nil,
+					Bindings: []*computepb.Binding{
+						{
+							Members: []string{
+								"allAuthenticatedUsers",
+							},
+							Role: ptr.P("roles/compute.imageUser"),
 						},
-						Role: ptr.P("roles/compute.imageUser"),
 					},
+					Version: ptr.P(int32(3)),
 				},
-				Version: ptr.P(int32(3)),
 			},
-		},
-		Project:  project,
-		Resource: image,
+			Project:  project,
+			Resource: image,
+		})
 	})
 	if err != nil {
 		return fmt.Errorf("cannot set IAM policy: %w", err)
@@ -478,13 +768,36 @@ func (p *gcp) makePublic(ctx context.Context, image string) error {
 	return nil
 }
 
+func (p *gcp) isPublic(ctx context.Context, project, image string) (bool, error) {
+	log.Debug(ctx, "Getting IAM policy")
+	policy, err := retried(ctx, "get IAM policy", func(ctx context.Context) (*computepb.Policy, error) {
+		return p.imagesClient.GetIamPolicy(ctx, &computepb.GetIamPolicyImageRequest{
+			Project:  project,
+			Resource: image,
+		}, withoutGAXRetries())
+	})
+	if err != nil {
+		return false, fmt.Errorf("cannot get IAM policy: %w", err)
+	}
+
+	for _, binding := range policy.GetBindings() {
+		if binding.GetRole() == "roles/compute.imageUser" && slices.Contains(binding.GetMembers(), "allAuthenticatedUsers") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (p *gcp) deleteImage(ctx context.Context, image string) error {
 	project := p.creds[p.pubCfg.Config].Project
 
 	log.Info(ctx, "Deleting image")
-	op, err := p.imagesClient.Delete(ctx, &computepb.DeleteImageRequest{
-		Image:   image,
-		Project: project,
+	op, err := retried(ctx, "delete image", func(ctx context.Context) (*computev1.Operation, error) {
+		return p.imagesClient.Delete(ctx, &computepb.DeleteImageRequest{
+			Image:   image,
+			Project: project,
+		})
 	})
 	if err != nil {
 		return fmt.Errorf("cannot delete image: %w", err)
@@ -498,10 +811,15 @@ func (p *gcp) deleteImage(ctx context.Context, image string) error {
 	log.Debug(ctx, "Ensuring that blob is deleted")
 	bucket := p.storageClient.Bucket(p.pubCfg.Bucket)
 	blob := bucket.Object(image + ".tar.gz")
-	err = blob.Delete(ctx)
+	err = retriedErr(ctx, "delete blob", blob.Retryer(storage.WithPolicy(storage.RetryNever)).Delete)
 	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
 		return fmt.Errorf("cannot delete blob %s: %w", blob.ObjectName(), err)
 	}
 
 	return nil
 }
+
+// withoutGAXRetries disables the retries of the SDK for a Compute Engine call which is retried by retried.
+func withoutGAXRetries() gax.CallOption {
+	return gax.WithRetry(nil)
+}
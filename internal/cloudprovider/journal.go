@@ -0,0 +1,156 @@
+package cloudprovider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// Journal records the completed steps of a publication in an ArtifactSource so that an interrupted publication can resume after the
+// last completed step.
+type Journal struct {
+	mutex  sync.Mutex
+	source ArtifactSource
+	key    string
+	steps  map[string]string
+}
+
+// LoadJournal retrieves a journal from an artifact source. A journal that does not exist yet is empty.
+func LoadJournal(ctx context.Context, source ArtifactSource, key string) (*Journal, error) {
+	j := &Journal{
+		source: source,
+		key:    key,
+		steps:  make(map[string]string),
+	}
+
+	body, err := source.GetObject(ctx, key)
+	if err != nil {
+		if errors.As(err, &KeyNotFoundError{}) {
+			return j, nil
+		}
+		return nil, err //nolint:wrapcheck // Directly wraps the source.
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	err = yaml.NewDecoder(body).Decode(&j.steps)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid journal: %w", err)
+	}
+	if j.steps == nil {
+		j.steps = make(map[string]string)
+	}
+
+	err = body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cannot close object: %w", err)
+	}
+
+	return j, nil
+}
+
+// Len returns the number of completed steps.
+func (j *Journal) Len() int {
+	if j == nil {
+		return 0
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return len(j.steps)
+}
+
+// Step returns the result of a step if it has been completed.
+func (j *Journal) Step(step string) (string, bool) {
+	if j == nil {
+		return "", false
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	result, ok := j.steps[step]
+	return result, ok
+}
+
+// Record records the result of a completed step and stores the journal.
+func (j *Journal) Record(ctx context.Context, step, result string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.steps[step] = result
+	return j.store(ctx)
+}
+
+// Clear forgets all completed steps and deletes the stored journal.
+func (j *Journal) Clear(ctx context.Context) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	clear(j.steps)
+	return j.source.DeleteObject(ctx, j.key) //nolint:wrapcheck // Directly wraps the source.
+}
+
+func (j *Journal) store(ctx context.Context) error {
+	var buf bytes.Buffer
+	if len(j.steps) > 0 {
+		err := yaml.NewEncoder(&buf).Encode(j.steps)
+		if err != nil {
+			return fmt.Errorf("invalid journal: %w", err)
+		}
+	}
+
+	return j.source.PutObject(ctx, j.key, &buf) //nolint:wrapcheck // Directly wraps the source.
+}
+
+// WithJournal stores a journal into the context for use by the publishing targets.
+func WithJournal(ctx context.Context, journal *Journal) context.Context {
+	return context.WithValue(ctx, ctxkJournal{}, journal)
+}
+
+type ctxkJournal struct{}
+
+func journalFromContext(ctx context.Context) *Journal {
+	journal, _ := ctx.Value(ctxkJournal{}).(*Journal)
+	return journal
+}
+
+// journaled runs a step unless the journal in the context shows that it has already been completed. The result of the step is
+// recorded in the journal.
+func journaled(ctx context.Context, step string, run func() (string, error)) (string, error) {
+	journal := journalFromContext(ctx)
+	result, ok := journal.Step(step)
+	if ok {
+		log.Info(ctx, "Step already completed, resuming", "step", step)
+		return result, nil
+	}
+
+	result, err := run()
+	if err != nil {
+		return "", err
+	}
+
+	err = journal.Record(ctx, step, result)
+	if err != nil {
+		return "", fmt.Errorf("cannot record step %s: %w", step, err)
+	}
+
+	return result, nil
+}
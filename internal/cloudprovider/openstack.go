@@ -4,19 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
 	"slices"
 	"strings"
-	"time"
 
 	"github.com/gophercloud/gophercloud/v2"
 	openstacksdk "github.com/gophercloud/gophercloud/v2/openstack"
+	"github.com/gophercloud/gophercloud/v2/openstack/identity/v3/tokens"
 	"github.com/gophercloud/gophercloud/v2/openstack/image/v2/imageimport"
 	"github.com/gophercloud/gophercloud/v2/openstack/image/v2/images"
+	"github.com/gophercloud/gophercloud/v2/pagination"
 
 	"github.com/gardenlinux/glci/internal/env"
 	"github.com/gardenlinux/glci/internal/gl"
 	"github.com/gardenlinux/glci/internal/log"
-	"github.com/gardenlinux/glci/internal/ptr"
 	"github.com/gardenlinux/glci/internal/slc"
 )
 
@@ -82,6 +84,7 @@ func (p *openstack) SetTargetConfig(ctx context.Context, cfg map[string]any, sou
 			continue
 		}
 
+		// Without RetryFunc and RetryBackoffFunc, the provider client does not retry failed calls on its own.
 		var providerClient *gophercloud.ProviderClient
 		providerClient, err = openstacksdk.AuthenticatedClient(ctx, gophercloud.AuthOptions{
 			IdentityEndpoint: proj.AuthURL,
@@ -119,26 +122,52 @@ func (*openstack) ImageSuffix() string {
 	return ".vmdk"
 }
 
+func (*openstack) Platforms() []string {
+	return []string{"openstack", "openstackbaremetal"}
+}
+
+func (p *openstack) Clouds() []string {
+	return []string{string(p.pubCfg.Hypervisor)}
+}
+
 func (p *openstack) IsPublished(manifest *gl.Manifest) (bool, error) {
 	if !p.isConfigured() {
 		return false, errors.New("config not set")
 	}
 
-	openstackOutput, err := publishingOutputFromManifest[openstackPublishingOutput](manifest)
-	if err != nil {
-		return false, err
+	// With a region filter, every selected region must have been published to.
+	published := p.publishedRegions(manifest)
+	if len(p.filter.Regions) > 0 {
+		for _, region := range p.filter.selectRegions(p.regions()) {
+			_, ok := published[region]
+			if !ok {
+				return false, nil
+			}
+		}
 	}
 
-	if openstackOutput.Images == nil {
-		return false, nil
+	return len(published) != 0, nil
+}
+
+func (p *openstack) PublishedImages(manifest *gl.Manifest) ([]PublishedImage, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
 	}
-	for _, img := range *openstackOutput.Images {
-		if img.Hypervisor == string(p.pubCfg.Hypervisor) {
-			return true, nil
+
+	openstackOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
+	var images []PublishedImage
+	for _, img := range openstackOutput.OpenStackImages {
+		if img.Hypervisor == string(p.pubCfg.Hypervisor) && p.filter.matchesRegion(img.Region) {
+			images = append(images, PublishedImage{
+				Cloud:  img.Hypervisor,
+				Region: img.Region,
+				ID:     img.ID,
+				Name:   img.Image,
+			})
 		}
 	}
 
-	return false, nil
+	return images, nil
 }
 
 func (p *openstack) AddOwnPublishingOutput(output, own PublishingOutput) (PublishingOutput, error) {
@@ -146,36 +175,23 @@ func (p *openstack) AddOwnPublishingOutput(output, own PublishingOutput) (Publis
 		return nil, errors.New("config not set")
 	}
 
-	openstackOutput, err := publishingOutput[openstackPublishingOutput](output)
-	if err != nil {
-		return nil, err
-	}
-	var ownOutput openstackPublishingOutput
-	ownOutput, err = publishingOutput[openstackPublishingOutput](own)
-	if err != nil {
-		return nil, err
-	}
+	openstackOutput := publishedImageMetadata(output)
+	ownOutput := publishedImageMetadata(own)
 
-	if ownOutput.Images == nil {
-		ownOutput.Images = &[]openstackPublishedImage{}
-	}
-	for _, img := range *ownOutput.Images {
+	for _, img := range ownOutput.OpenStackImages {
 		if img.Hypervisor != string(p.pubCfg.Hypervisor) {
 			return nil, errors.New("new publishing output has extraneous entries")
 		}
 	}
 
-	if openstackOutput.Images == nil {
-		return &ownOutput, nil
-	}
-	for _, img := range *openstackOutput.Images {
-		if img.Hypervisor == string(p.pubCfg.Hypervisor) {
-			return nil, errors.New("cannot add publishing output to existing publishing output")
-		}
-	}
-
-	ownOutput.Images = ptr.P(slices.Concat(*openstackOutput.Images, *ownOutput.Images))
-	return &ownOutput, nil
+	// A publication restricted to some regions adds to the images already published in the other regions.
+	openstackOutput.OpenStackImages = slices.DeleteFunc(slices.Clone(openstackOutput.OpenStackImages), func(img gl.OpenStackImage) bool {
+		return slices.ContainsFunc(ownOutput.OpenStackImages, func(ownImg gl.OpenStackImage) bool {
+			return ownImg.Hypervisor == img.Hypervisor && ownImg.Region == img.Region
+		})
+	})
+	openstackOutput.OpenStackImages = slices.Concat(openstackOutput.OpenStackImages, ownOutput.OpenStackImages)
+	return &openstackOutput, nil
 }
 
 func (p *openstack) RemoveOwnPublishingOutput(output PublishingOutput) (PublishingOutput, error) {
@@ -183,26 +199,17 @@ func (p *openstack) RemoveOwnPublishingOutput(output PublishingOutput) (Publishi
 		return nil, errors.New("config not set")
 	}
 
-	openstackOutput, err := publishingOutput[openstackPublishingOutput](output)
-	if err != nil {
-		return nil, err
-	}
+	openstackOutput := publishedImageMetadata(output)
 
-	var otherImages []openstackPublishedImage
-	if openstackOutput.Images != nil {
-		for _, img := range *openstackOutput.Images {
-			if img.Hypervisor != string(p.pubCfg.Hypervisor) {
-				otherImages = append(otherImages, img)
-			}
+	var otherImages []gl.OpenStackImage
+	for _, img := range openstackOutput.OpenStackImages {
+		if img.Hypervisor != string(p.pubCfg.Hypervisor) || !p.filter.matchesRegion(img.Region) {
+			otherImages = append(otherImages, img)
 		}
 	}
-	if len(otherImages) == 0 {
-		return nil, nil
-	}
+	openstackOutput.OpenStackImages = otherImages
 
-	return &openstackPublishingOutput{
-		Images: &otherImages,
-	}, nil
+	return remainingPublishingOutput(openstackOutput), nil
 }
 
 func (p *openstack) Publish(ctx context.Context, cname string, manifest *gl.Manifest, sources map[string]ArtifactSource) (PublishingOutput,
@@ -227,13 +234,11 @@ func (p *openstack) Publish(ctx context.Context, cname string, manifest *gl.Mani
 	ctx = log.WithValues(ctx, "image", image, "hypervisor", p.pubCfg.Hypervisor, "architecture", arch, "sourceType", source.Type(),
 		"sourceRepo", source.Repository())
 
-	regions := p.listRegions()
-	if p.pubCfg.Regions != nil {
-		regions = slc.Subset(regions, *p.pubCfg.Regions)
-	}
+	regions := p.filter.narrowRegions(ctx, p.regions(), "")
 	if len(regions) == 0 {
 		return nil, errors.New("no available regions")
 	}
+	regions = unpublishedRegions(ctx, regions, p.publishedRegions(manifest))
 
 	sourceChina := source
 	if p.pubCfg.SourceChina != nil {
@@ -241,6 +246,7 @@ func (p *openstack) Publish(ctx context.Context, cname string, manifest *gl.Mani
 	}
 
 	imgs := make(map[string]string, len(regions))
+	verified := make(map[ArtifactSource]struct{}, 2)
 	for _, region := range regions {
 		imageClient := p.imagesClients[region]
 		src := source
@@ -249,11 +255,25 @@ func (p *openstack) Publish(ctx context.Context, cname string, manifest *gl.Mani
 		}
 		lctx := log.WithValues(ctx, "region", region)
 
+		_, ok := verified[src]
+		if !ok {
+			_, err = journaled(lctx, "checksum/"+src.Repository(), func() (string, error) {
+				return imagePath.S3Key, verifyReleaseFile(lctx, src, imagePath)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("cannot verify image %s: %w", image, err)
+			}
+			verified[src] = struct{}{}
+		}
+
 		var imageID string
-		imageID, err = p.createImage(lctx, imageClient, src, imagePath.S3Key, image)
+		imageID, err = journaled(lctx, "image/"+region, func() (string, error) {
+			return p.createImage(lctx, imageClient, src, imagePath.S3Key, image)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("cannot create image for region %s: %w", region, err)
 		}
+		p.trackImage(lctx, "image/"+region, imageClient, imageID)
 
 		imgs[region] = imageID
 	}
@@ -263,44 +283,218 @@ func (p *openstack) Publish(ctx context.Context, cname string, manifest *gl.Mani
 		return nil, fmt.Errorf("cannot finalize images: %w", err)
 	}
 
-	outputImages := make([]openstackPublishedImage, 0, len(imgs))
+	outputImages := make([]gl.OpenStackImage, 0, len(imgs))
 	for region, imageID := range imgs {
-		outputImages = append(outputImages, openstackPublishedImage{
+		outputImages = append(outputImages, gl.OpenStackImage{
 			Region:     region,
 			ID:         imageID,
 			Image:      image,
 			Hypervisor: string(p.pubCfg.Hypervisor),
 		})
 	}
-	return &openstackPublishingOutput{
-		Images: &outputImages,
+	return &gl.PublishedImageMetadata{
+		OpenStackImages: outputImages,
 	}, nil
 }
 
-func (p *openstack) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]ArtifactSource) error {
+func (p *openstack) Verify(ctx context.Context, manifest *gl.Manifest) ([]ImageProblem, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type(), "hypervisor", p.pubCfg.Hypervisor)
+
+	imgs, err := p.PublishedImages(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	regions := p.regions()
+
+	var problems []ImageProblem
+	found := make(map[string]struct{}, len(regions))
+	for _, img := range imgs {
+		found[img.Region] = struct{}{}
+		imageClient, ok := p.imagesClients[img.Region]
+		if !ok {
+			return nil, fmt.Errorf("cannot verify image %s: region %s is not configured", img.ID, img.Region)
+		}
+		lctx := log.WithValues(ctx, "region", img.Region, "imageID", img.ID)
+
+		log.Debug(lctx, "Verifying image")
+		var image *images.Image
+		image, err = retried(lctx, "get image", func(ctx context.Context) (*images.Image, error) {
+			return images.Get(ctx, imageClient, img.ID).Extract()
+		})
+		if err != nil {
+			if gophercloud.ResponseCodeIs(err, http.StatusNotFound) {
+				problems = append(problems, ImageProblem{
+					Image:   img,
+					Problem: ProblemDeleted,
+				})
+				continue
+			}
+			return nil, fmt.Errorf("cannot get image %s in region %s: %w", img.ID, img.Region, err)
+		}
+		if image.Status != images.ImageStatusActive {
+			problems = append(problems, ImageProblem{
+				Image:   img,
+				Problem: ProblemNotAvailable,
+				Detail:  "status " + string(image.Status),
+			})
+			continue
+		}
+		if image.Visibility != images.ImageVisibilityPublic && image.Visibility != images.ImageVisibilityCommunity {
+			problems = append(problems, ImageProblem{
+				Image:   img,
+				Problem: ProblemPrivate,
+				Detail:  "visibility " + string(image.Visibility),
+			})
+		}
+	}
+	for _, region := range regions {
+		_, ok := found[region]
+		if !ok {
+			problems = append(problems, ImageProblem{
+				Image: PublishedImage{
+					Cloud:  string(p.pubCfg.Hypervisor),
+					Region: region,
+				},
+				Problem: ProblemMissingRegion,
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+func (p *openstack) SetFilter(ctx context.Context, filter Filter) (bool, error) {
+	if !p.isConfigured() {
+		return false, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type(), "hypervisor", p.pubCfg.Hypervisor)
+
+	if !filter.matchesCloud(string(p.pubCfg.Hypervisor)) {
+		log.Info(ctx, "Excluding hypervisor")
+		return false, nil
+	}
+
+	regions := p.regions()
+	if !filter.matchesAnyRegion(regions) {
+		log.Info(ctx, "Excluding hypervisor, no region selected")
+		return false, nil
+	}
+	p.filter = filter
+
+	return true, nil
+}
+
+func (p *openstack) Orphans(ctx context.Context, published []PublishedImage) ([]Resource, error) {
+	if !p.isConfigured() {
+		return nil, errors.New("config not set")
+	}
+	ctx = log.WithValues(ctx, "target", p.Type(), "hypervisor", p.pubCfg.Hypervisor)
+
+	referenced := make(map[string]struct{}, len(published))
+	for _, img := range published {
+		referenced[img.ID] = struct{}{}
+	}
+	pattern := p.imageNamePattern()
+
+	var orphans []Resource
+	for region, imageClient := range p.imagesClients {
+		lctx := log.WithValues(ctx, "region", region)
+
+		owner, err := p.projectID(imageClient)
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine project in region %s: %w", region, err)
+		}
+
+		log.Debug(lctx, "Listing images")
+		var pages pagination.Page
+		pages, err = retried(lctx, "list images", func(ctx context.Context) (pagination.Page, error) {
+			return images.List(imageClient, images.ListOpts{
+				Owner: owner,
+			}).AllPages(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot list images in region %s: %w", region, err)
+		}
+		var imgs []images.Image
+		imgs, err = images.ExtractImages(pages)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list images in region %s: %w", region, err)
+		}
+
+		for _, img := range imgs {
+			if !pattern.MatchString(img.Name) {
+				continue
+			}
+			_, ok := referenced[img.ID]
+			if ok {
+				continue
+			}
+			orphans = append(orphans, Resource{
+				Kind:    ResourceKindImage,
+				Cloud:   string(p.pubCfg.Hypervisor),
+				Region:  region,
+				ID:      img.ID,
+				Name:    img.Name,
+				Created: img.CreatedAt,
+			})
+		}
+	}
+
+	return orphans, nil
+}
+
+func (p *openstack) DeleteOrphan(ctx context.Context, resource Resource) error {
 	if !p.isConfigured() {
 		return errors.New("config not set")
 	}
-	ctx = log.WithValues(ctx, "target", p.Type())
+	ctx = log.WithValues(ctx, "target", p.Type(), "hypervisor", p.pubCfg.Hypervisor, "region", resource.Region, "imageID", resource.ID)
+
+	if resource.Kind != ResourceKindImage {
+		return fmt.Errorf("unsupported resource kind %s", resource.Kind)
+	}
+	imageClient, ok := p.imagesClients[resource.Region]
+	if !ok {
+		return fmt.Errorf("region %s is not configured", resource.Region)
+	}
 
-	pubOut, err := publishingOutputFromManifest[*openstackPublishingOutput](manifest)
+	log.Info(ctx, "Deleting image")
+	err := retriedErr(ctx, "delete image", func(ctx context.Context) error {
+		return images.Delete(ctx, imageClient, resource.ID).ExtractErr()
+	})
 	if err != nil {
-		return fmt.Errorf("invalid manifest: %w", err)
+		return fmt.Errorf("cannot delete image %s: %w", resource.ID, err)
+	}
+
+	return nil
+}
+
+func (p *openstack) Remove(ctx context.Context, manifest *gl.Manifest, _ map[string]ArtifactSource) error {
+	if !p.isConfigured() {
+		return errors.New("config not set")
 	}
-	if pubOut == nil || pubOut.Images == nil {
+	ctx = log.WithValues(ctx, "target", p.Type())
+
+	pubOut := publishedImageMetadata(manifest.PublishedImageMetadata)
+	if len(pubOut.OpenStackImages) == 0 {
 		return errors.New("invalid manifest: missing published images")
 	}
 
 	ctx = log.WithValues(ctx, "hypervisor", p.pubCfg.Hypervisor)
 
-	for _, img := range *pubOut.Images {
-		if img.Hypervisor != string(p.pubCfg.Hypervisor) {
+	for _, img := range pubOut.OpenStackImages {
+		if img.Hypervisor != string(p.pubCfg.Hypervisor) || !p.filter.matchesRegion(img.Region) {
 			continue
 		}
 		lctx := log.WithValues(ctx, "region", img.Region, "imageID", img.ID)
 
 		log.Info(lctx, "Deleting image")
-		err = images.Delete(lctx, p.imagesClients[img.Region], img.ID).ExtractErr()
+		err := retriedErr(lctx, "delete image", func(ctx context.Context) error {
+			return images.Delete(ctx, p.imagesClients[img.Region], img.ID).ExtractErr()
+		})
 		if err != nil {
 			return fmt.Errorf("cannot delete image %s in region %s: %w", img.ID, img.Region, err)
 		}
@@ -312,6 +506,7 @@ func (p *openstack) Remove(ctx context.Context, manifest *gl.Manifest, _ map[str
 type openstack struct {
 	creds         map[string]openstackCredentials
 	pubCfg        openstackPublishingConfig
+	filter        Filter
 	imagesClients map[string]*gophercloud.ServiceClient
 }
 
@@ -348,22 +543,20 @@ const (
 	openstackHypervisorVMware    openstackHypervisor = "VMware"
 )
 
-type openstackPublishingOutput struct {
-	Images *[]openstackPublishedImage `yaml:"published_openstack_images,omitempty"`
+func (p *openstack) isConfigured() bool {
+	return len(p.imagesClients) != 0
 }
 
-type openstackPublishedImage struct {
-	Region     string `yaml:"region_name"`
-	ID         string `yaml:"image_id"`
-	Image      string `yaml:"image_name"`
-	Hypervisor string `yaml:"hypervisor"`
+func (p *openstack) imageName(cname, version, committish string) string {
+	return fmt.Sprintf("gardenlinux-%s-%s-%s-%.8s", cname, p.imageNameHypervisor(), version, committish)
 }
 
-func (p *openstack) isConfigured() bool {
-	return len(p.imagesClients) != 0
+// imageNamePattern matches the names of all images created by GLCI for the configured hypervisor.
+func (p *openstack) imageNamePattern() *regexp.Regexp {
+	return regexp.MustCompile(`^gardenlinux-.+-` + regexp.QuoteMeta(p.imageNameHypervisor()) + `-[^-]+-[0-9a-f]{1,8}$`)
 }
 
-func (p *openstack) imageName(cname, version, committish string) string {
+func (p *openstack) imageNameHypervisor() string {
 	var hypervisor string
 	switch p.pubCfg.Hypervisor {
 	case openstackHypervisorBareMetal:
@@ -376,7 +569,7 @@ func (p *openstack) imageName(cname, version, committish string) string {
 		hypervisor += "-test"
 	}
 
-	return fmt.Sprintf("gardenlinux-%s-%s-%s-%.8s", cname, hypervisor, version, committish)
+	return hypervisor
 }
 
 func (*openstack) architecture(arch gl.Architecture) (string, error) {
@@ -390,6 +583,29 @@ func (*openstack) architecture(arch gl.Architecture) (string, error) {
 	}
 }
 
+// regions returns the configured regions which are published to.
+func (p *openstack) regions() []string {
+	regions := p.listRegions()
+	if p.pubCfg.Regions != nil {
+		regions = slc.Subset(regions, *p.pubCfg.Regions)
+	}
+
+	return regions
+}
+
+// publishedRegions returns the IDs of the images of the hypervisor which are recorded in a manifest by region.
+func (p *openstack) publishedRegions(manifest *gl.Manifest) map[string]string {
+	openstackOutput := publishedImageMetadata(manifest.PublishedImageMetadata)
+	published := make(map[string]string, len(openstackOutput.OpenStackImages))
+	for _, img := range openstackOutput.OpenStackImages {
+		if img.Hypervisor == string(p.pubCfg.Hypervisor) {
+			published[img.Region] = img.ID
+		}
+	}
+
+	return published
+}
+
 func (p *openstack) listRegions() []string {
 	projects := p.creds[p.pubCfg.Config].Projects
 
@@ -439,10 +655,12 @@ func (p *openstack) createImage(ctx context.Context, imageClient *gophercloud.Se
 	}
 
 	log.Debug(ctx, "Importing image")
-	err = imageimport.Create(ctx, imageClient, img.ID, imageimport.CreateOpts{
-		Name: imageimport.WebDownloadMethod,
-		URI:  source.GetObjectURL(key),
-	}).ExtractErr()
+	err = retriedErr(ctx, "import image", func(ctx context.Context) error {
+		return imageimport.Create(ctx, imageClient, img.ID, imageimport.CreateOpts{
+			Name: imageimport.WebDownloadMethod,
+			URI:  source.GetObjectURL(key),
+		}).ExtractErr()
+	})
 	if err != nil {
 		return "", fmt.Errorf("cannot import image: %w", err)
 	}
@@ -453,25 +671,56 @@ func (p *openstack) createImage(ctx context.Context, imageClient *gophercloud.Se
 func (p *openstack) waitForImages(ctx context.Context, imgs map[string]string) error {
 	for region, imageID := range imgs {
 		imagesClient := p.imagesClients[region]
-		var status images.ImageStatus
-		for status != images.ImageStatusActive {
-			log.Debug(ctx, "Waiting for image", "region", region, "imageID", imageID)
-			img, err := images.Get(ctx, imagesClient, imageID).Extract()
+		err := poll(ctx, PollStepImport, "image "+imageID+" in region "+region, func(ctx context.Context) (bool, string, error) {
+			img, err := retried(ctx, "get image", func(ctx context.Context) (*images.Image, error) {
+				return images.Get(ctx, imagesClient, imageID).Extract()
+			})
 			if err != nil {
-				return fmt.Errorf("cannot get image %s in region %s: %w", imageID, region, err)
+				return false, "", fmt.Errorf("cannot get image %s in region %s: %w", imageID, region, err)
 			}
-			status = img.Status
-
-			if status != images.ImageStatusActive {
-				if status != images.ImageStatusQueued && status != images.ImageStatusSaving && status != images.ImageStatusImporting {
-					return fmt.Errorf("image %s in region %s has status %s", imageID, region, status)
-				}
-
-				time.Sleep(time.Second * 7)
+			status := img.Status
+			if status != images.ImageStatusActive && status != images.ImageStatusQueued && status != images.ImageStatusSaving &&
+				status != images.ImageStatusImporting {
+				return false, "", fmt.Errorf("image %s in region %s has status %s", imageID, region, status)
 			}
+
+			return status == images.ImageStatusActive, string(status), nil
+		})
+		if err != nil {
+			return err
 		}
 	}
 	log.Info(ctx, "Images ready", "count", len(imgs))
 
 	return nil
 }
+
+func (*openstack) projectID(imageClient *gophercloud.ServiceClient) (string, error) {
+	authResult, ok := imageClient.GetAuthResult().(tokens.CreateResult)
+	if !ok {
+		return "", errors.New("missing authentication result")
+	}
+	project, err := authResult.ExtractProject()
+	if err != nil {
+		return "", fmt.Errorf("cannot extract project: %w", err)
+	}
+	if project == nil || project.ID == "" {
+		return "", errors.New("missing project")
+	}
+
+	return project.ID, nil
+}
+
+func (*openstack) trackImage(ctx context.Context, step string, imageClient *gophercloud.ServiceClient, imageID string) {
+	track(ctx, step, "image "+imageID, func(ctx context.Context) error {
+		log.Info(ctx, "Deleting image")
+		err := retriedErr(ctx, "delete image", func(ctx context.Context) error {
+			return images.Delete(ctx, imageClient, imageID).ExtractErr()
+		})
+		if err != nil {
+			return fmt.Errorf("cannot delete image %s: %w", imageID, err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package cloudprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// PollStep identifies a kind of long-running operation which is polled until it completes.
+type PollStep string
+
+const (
+	// PollStepImport is the import of an image or snapshot from an artifact.
+	PollStepImport PollStep = "import"
+	// PollStepCopy is the copy of an image to another region.
+	PollStepCopy PollStep = "copy"
+	// PollStepReplication is the replication of an image version to the regions of a gallery.
+	PollStepReplication PollStep = "replication"
+)
+
+// PollPolicy controls how long-running operations of cloud providers are polled and how long they may take.
+type PollPolicy struct {
+	// Interval is the delay before the first poll.
+	Interval time.Duration
+	// Backoff is the factor the interval grows by after every poll.
+	Backoff float64
+	// MaxInterval caps the delay between polls.
+	MaxInterval time.Duration
+	// Deadlines limits the duration of every step, a missing or zero deadline means no limit.
+	Deadlines map[PollStep]time.Duration
+}
+
+// DefaultPollPolicy is used unless a different policy has been stored in the context.
+//
+//nolint:gochecknoglobals // Read-only default.
+var DefaultPollPolicy = PollPolicy{
+	Interval:    time.Second * 7,
+	Backoff:     1.5,
+	MaxInterval: time.Minute,
+	Deadlines: map[PollStep]time.Duration{
+		PollStepImport:      2 * time.Hour,
+		PollStepCopy:        90 * time.Minute,
+		PollStepReplication: 3 * time.Hour,
+	},
+}
+
+// WithPollPolicy stores a poll policy into the context for use by the publishing targets.
+func WithPollPolicy(ctx context.Context, policy PollPolicy) context.Context {
+	return context.WithValue(ctx, ctxkPollPolicy{}, policy)
+}
+
+type ctxkPollPolicy struct{}
+
+func pollPolicyFromContext(ctx context.Context) PollPolicy {
+	policy, ok := ctx.Value(ctxkPollPolicy{}).(PollPolicy)
+	if !ok {
+		return DefaultPollPolicy
+	}
+
+	return policy
+}
+
+// TimeoutError indicates that a long-running operation has not completed before the deadline of its step.
+type TimeoutError struct {
+	Step     PollStep
+	Resource string
+	State    string
+	Deadline time.Duration
+}
+
+func (e TimeoutError) Error() string {
+	state := e.State
+	if state == "" {
+		state = "unknown"
+	}
+
+	return fmt.Sprintf("%s of %s has not completed within %s, last observed state: %s", e.Step, e.Resource, e.Deadline, state)
+}
+
+// poll calls check until it reports that the operation of a step on a resource is done, waiting between the calls with backoff. The
+// state reported by check is logged and included in the TimeoutError returned when the deadline of the step passes.
+func poll(ctx context.Context, step PollStep, resource string, check func(ctx context.Context) (bool, string, error)) error {
+	policy := pollPolicyFromContext(ctx)
+	deadline := policy.Deadlines[step]
+	pctx := ctx
+	if deadline > 0 {
+		var cancel context.CancelFunc
+		pctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
+	timeout := func(state string) error {
+		return TimeoutError{
+			Step:     step,
+			Resource: resource,
+			State:    state,
+			Deadline: deadline,
+		}
+	}
+
+	var state string
+	interval := policy.Interval
+	for {
+		done, current, err := check(pctx)
+		if err != nil {
+			if pctx.Err() != nil && ctx.Err() == nil {
+				return timeout(state)
+			}
+			return err
+		}
+		if done {
+			return nil
+		}
+		if current != state {
+			log.Debug(ctx, "Waiting", "step", step, "resource", resource, "state", current)
+			state = current
+		}
+
+		select {
+		case <-pctx.Done():
+			if ctx.Err() != nil {
+				return fmt.Errorf("cannot wait for %s of %s: %w", step, resource, context.Cause(ctx))
+			}
+			return timeout(state)
+		case <-time.After(interval):
+		}
+		interval = min(time.Duration(float64(interval)*max(policy.Backoff, 1)), max(policy.MaxInterval, policy.Interval))
+	}
+}
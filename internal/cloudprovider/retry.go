@@ -0,0 +1,180 @@
+package cloudprovider
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/aliyun/alibabacloud-oss-go-sdk-v2/oss"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/gophercloud/gophercloud/v2"
+	"google.golang.org/api/googleapi"
+
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// RetryPolicy controls how API calls to cloud providers are retried when they fail with a transient error, such as throttling or a
+// temporarily unavailable service. Other errors are never retried.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts of an API call, including the first one.
+	Attempts int
+	// InitialBackoff is the delay before the first retry, it doubles with every further retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+	// Timeout limits the duration of a single attempt, zero means no limit.
+	Timeout time.Duration
+}
+
+// DefaultRetryPolicy is used unless a different policy has been stored in the context.
+//
+//nolint:gochecknoglobals // Read-only default.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:       5,
+	InitialBackoff: 2 * time.Second,
+	MaxBackoff:     time.Minute,
+}
+
+// WithRetryPolicy stores a retry policy into the context for use by the artifact sources and publishing targets.
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, ctxkRetryPolicy{}, policy)
+}
+
+type ctxkRetryPolicy struct{}
+
+func retryPolicyFromContext(ctx context.Context) RetryPolicy {
+	policy, ok := ctx.Value(ctxkRetryPolicy{}).(RetryPolicy)
+	if !ok {
+		return DefaultRetryPolicy
+	}
+
+	return policy
+}
+
+// retried runs an API call of a step, retrying it with exponential backoff and jitter for as long as it fails with a transient error.
+// Calls which stream a request or response body must not be retried since the body can be consumed only once.
+func retried[T any](ctx context.Context, step string, call func(ctx context.Context) (T, error)) (T, error) {
+	policy := retryPolicyFromContext(ctx)
+	backoff := min(policy.InitialBackoff, policy.MaxBackoff)
+
+	for attempt := 1; ; attempt++ {
+		result, timedOut, err := tryOnce(ctx, policy.Timeout, call)
+		if err == nil {
+			return result, nil
+		}
+		if attempt >= policy.Attempts || (!timedOut && !isTransient(err)) {
+			return result, err
+		}
+
+		delay := backoff/2 + rand.N(backoff/2+1) //nolint:gosec // Jitter does not need a secure random number generator.
+		log.Info(ctx, "Transient failure, retrying", "step", step, "attempt", attempt, "delay", delay, "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return result, errors.Join(err, context.Cause(ctx))
+		case <-time.After(delay):
+		}
+		backoff = min(backoff*2, policy.MaxBackoff)
+	}
+}
+
+// retriedErr is retried for API calls without a result.
+func retriedErr(ctx context.Context, step string, call func(ctx context.Context) error) error {
+	_, err := retried(ctx, step, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	})
+	return err
+}
+
+// tryOnce runs a single attempt of an API call and reports whether the attempt has exceeded its timeout. Azure SDK calls of the attempt
+// are not retried by the SDK, other SDKs have their retries disabled where the calls are made.
+func tryOnce[T any](ctx context.Context, timeout time.Duration, call func(ctx context.Context) (T, error)) (T, bool, error) {
+	ctx = policy.WithRetryOptions(ctx, policy.RetryOptions{
+		MaxRetries: -1,
+	})
+
+	if timeout <= 0 {
+		result, err := call(ctx)
+		return result, false, err
+	}
+
+	actx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := call(actx)
+	return result, err != nil && actx.Err() != nil && ctx.Err() == nil, err
+}
+
+// isTransient sorts errors returned by the SDKs of the cloud providers into transient and permanent ones.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if awsretry.IsErrorRetryables(awsretry.DefaultRetryables).IsErrorRetryable(err) == awssdk.TrueTernary {
+		return true
+	}
+
+	var azureErr *azcore.ResponseError
+	if errors.As(err, &azureErr) {
+		return isTransientStatus(azureErr.StatusCode)
+	}
+
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return isTransientStatus(googleErr.Code)
+	}
+
+	// The Alibaba Cloud SDK returns different error types depending on the API, all of which provide a code and a status code.
+	var aliyunErr interface {
+		GetCode() *string
+		GetStatusCode() *int
+	}
+	if errors.As(err, &aliyunErr) {
+		code := aliyunErr.GetCode()
+		if code != nil && slices.Contains(aliyunTransientCodes, *code) {
+			return true
+		}
+		status := aliyunErr.GetStatusCode()
+		return status != nil && isTransientStatus(*status)
+	}
+
+	var ossErr *oss.ServiceError
+	if errors.As(err, &ossErr) {
+		return isTransientStatus(ossErr.StatusCode) || slices.Contains(aliyunTransientCodes, ossErr.Code)
+	}
+
+	var openstackErr gophercloud.ErrUnexpectedResponseCode
+	if errors.As(err, &openstackErr) {
+		return isTransientStatus(openstackErr.Actual)
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func isTransientStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+//nolint:gochecknoglobals // Read-only list.
+var aliyunTransientCodes = []string{
+	"Throttling",
+	"Throttling.User",
+	"Throttling.Api",
+	"ServiceUnavailable",
+	"InternalError",
+	"SlowDown",
+}
@@ -0,0 +1,119 @@
+package cloudprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// Rollback tracks the resources created by a publication so that they can be deleted again if the publication fails.
+type Rollback struct {
+	mutex     sync.Mutex
+	resources []trackedResource
+}
+
+type trackedResource struct {
+	step        string
+	description string
+	undo        func(ctx context.Context) error
+}
+
+// Len returns the number of tracked resources.
+func (r *Rollback) Len() int {
+	if r == nil {
+		return 0
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.resources)
+}
+
+// Run deletes all tracked resources in reverse order of their creation. It carries on after a failed deletion, logs every resource
+// it could not delete and returns all failures joined together. Nothing is deleted if the context has already been cancelled, since an
+// interrupted publication is resumed from its journal, which still refers to the resources.
+func (r *Rollback) Run(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if ctx.Err() != nil {
+		log.Info(ctx, "Keeping resources of interrupted publication", "resources", len(r.resources))
+		return nil
+	}
+
+	// Once started, the rollback is completed even if the publication is cancelled in the meantime.
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for _, resource := range slices.Backward(r.resources) {
+		lctx := log.WithValues(ctx, "step", resource.step, "resource", resource.description)
+
+		log.Info(lctx, "Rolling back resource")
+		err := resource.undo(lctx)
+		if err != nil {
+			err = fmt.Errorf("cannot roll back %s: %w", resource.description, err)
+			log.Error(lctx, err)
+			errs = append(errs, err)
+		}
+	}
+	r.resources = nil
+
+	return errors.Join(errs...)
+}
+
+func (r *Rollback) track(step, description string, undo func(ctx context.Context) error) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.resources = append(r.resources, trackedResource{
+		step:        step,
+		description: description,
+		undo:        undo,
+	})
+}
+
+func (r *Rollback) untrack(step string) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.resources = slices.DeleteFunc(r.resources, func(resource trackedResource) bool {
+		return resource.step == step
+	})
+}
+
+// WithRollback stores a rollback into the context for use by the publishing targets.
+func WithRollback(ctx context.Context, rollback *Rollback) context.Context {
+	return context.WithValue(ctx, ctxkRollback{}, rollback)
+}
+
+type ctxkRollback struct{}
+
+// track registers a resource that has been created by a step of a publication with the rollback in the context.
+func track(ctx context.Context, step, description string, undo func(ctx context.Context) error) {
+	rollback, _ := ctx.Value(ctxkRollback{}).(*Rollback)
+	rollback.track(step, description, undo)
+}
+
+// untrack removes the resource created by a step from the rollback in the context once it is no longer needed or owned by another
+// resource.
+func untrack(ctx context.Context, step string) {
+	rollback, _ := ctx.Value(ctxkRollback{}).(*Rollback)
+	rollback.untrack(step)
+}
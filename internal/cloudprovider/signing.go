@@ -0,0 +1,321 @@
+package cloudprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// ManifestSignatureSuffix ends the keys of detached manifest signatures. A signature is stored beneath the key of its manifest followed by
+// the SHA-256 digest of the signed content, so that a signature is written before the manifest it belongs to and never replaced.
+const ManifestSignatureSuffix = ".sig"
+
+const (
+	signatureAlgorithmEd25519 = "ed25519"
+	signatureAlgorithmECDSA   = "ecdsa-sha256"
+)
+
+// ManifestSigning controls how manifests written to an artifact source are signed and how the signatures of manifests read from it are
+// verified. Several trusted keys allow rotating the signing key without invalidating manifests signed before.
+type ManifestSigning struct {
+	// Signer signs manifests written by PutManifest, manifests are not signed if it is nil.
+	Signer crypto.Signer
+	// TrustedKeys are the public keys whose signatures are accepted by GetManifest.
+	TrustedKeys []crypto.PublicKey
+	// RequireSigned rejects manifests written by GLCI without a signature, otherwise only manifests with an invalid signature are rejected.
+	// Manifests of the build system, which have neither a schema version nor published image metadata, are never signed and always
+	// accepted.
+	RequireSigned bool
+}
+
+// WithManifestSigning returns an artifact source whose manifests are signed and verified according to a signing policy by PutManifest and
+// GetManifest. All other objects are passed through unchanged.
+func WithManifestSigning(source ArtifactSource, signing ManifestSigning) ArtifactSource {
+	return &signingSource{
+		ArtifactSource: source,
+		signing:        signing,
+	}
+}
+
+type signingSource struct {
+	ArtifactSource
+	signing ManifestSigning
+}
+
+// IsManifestSignature returns whether a key is the key of a detached manifest signature rather than of a manifest.
+func IsManifestSignature(key string) bool {
+	return strings.HasSuffix(key, ManifestSignatureSuffix)
+}
+
+// ParseManifestSigningKey parses a PEM encoded PKCS #8 Ed25519 or ECDSA private key.
+func ParseManifestSigningKey(pemKey string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// ParseManifestVerificationKey parses a PEM encoded PKIX Ed25519 or ECDSA public key.
+func ParseManifestVerificationKey(pemKey string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return key, nil
+	case *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+type manifestSignature struct {
+	Algorithm string `yaml:"algorithm"`
+	KeyID     string `yaml:"key_id"`
+	Signature string `yaml:"signature"`
+}
+
+func manifestSigningOf(source ArtifactSource) (ManifestSigning, bool) {
+	s, ok := source.(*signingSource)
+	if !ok {
+		return ManifestSigning{}, false
+	}
+
+	return s.signing, true
+}
+
+// keyID identifies a public key by the SHA-256 digest of its PKIX encoding.
+func keyID(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// signedPayload returns what is actually signed for a manifest. It includes the key of the manifest, so that a manifest together with
+// its signature cannot be replayed under the key of another manifest.
+func signedPayload(key string, manifest []byte) []byte {
+	payload := make([]byte, 0, len(key)+1+len(manifest))
+	payload = append(payload, key...)
+	payload = append(payload, 0)
+
+	return append(payload, manifest...)
+}
+
+func signManifest(signer crypto.Signer, key string, manifest []byte) (manifestSignature, error) {
+	id, err := keyID(signer.Public())
+	if err != nil {
+		return manifestSignature{}, err
+	}
+	payload := signedPayload(key, manifest)
+
+	var algorithm string
+	var sig []byte
+	switch signer.Public().(type) {
+	case ed25519.PublicKey:
+		algorithm = signatureAlgorithmEd25519
+		sig, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	case *ecdsa.PublicKey:
+		algorithm = signatureAlgorithmECDSA
+		digest := sha256.Sum256(payload)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return manifestSignature{}, fmt.Errorf("unsupported signing key type %T", signer.Public())
+	}
+	if err != nil {
+		return manifestSignature{}, fmt.Errorf("cannot sign manifest: %w", err)
+	}
+
+	return manifestSignature{
+		Algorithm: algorithm,
+		KeyID:     id,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}, nil
+}
+
+func verifyManifestSignature(trustedKeys []crypto.PublicKey, key string, manifest []byte, signature manifestSignature) error {
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	payload := signedPayload(key, manifest)
+
+	for _, trustedKey := range trustedKeys {
+		var id string
+		id, err = keyID(trustedKey)
+		if err != nil {
+			return err
+		}
+		if id != signature.KeyID {
+			continue
+		}
+
+		switch trustedKey := trustedKey.(type) {
+		case ed25519.PublicKey:
+			if signature.Algorithm == signatureAlgorithmEd25519 && ed25519.Verify(trustedKey, payload, sig) {
+				return nil
+			}
+		case *ecdsa.PublicKey:
+			digest := sha256.Sum256(payload)
+			if signature.Algorithm == signatureAlgorithmECDSA && ecdsa.VerifyASN1(trustedKey, digest[:], sig) {
+				return nil
+			}
+		}
+
+		return errors.New("signature does not match")
+	}
+
+	return fmt.Errorf("signed by untrusted key %s", signature.KeyID)
+}
+
+// ErrManifestNotSigned is returned for a manifest written by GLCI without a signature if signatures are required.
+var ErrManifestNotSigned = errors.New("manifest is not signed")
+
+// verifyManifest verifies the detached signature of a manifest if the artifact source has a signing policy. If a signature is required
+// but missing, ErrManifestNotSigned is returned, so that the caller can still accept manifests of the build system.
+func verifyManifest(ctx context.Context, source ArtifactSource, key string, manifest []byte) error {
+	signing, ok := manifestSigningOf(source)
+	if !ok || (len(signing.TrustedKeys) == 0 && !signing.RequireSigned) {
+		return nil
+	}
+
+	body, err := source.GetObject(ctx, manifestSignatureKey(key, manifest))
+	if err != nil {
+		if errors.As(err, &KeyNotFoundError{}) {
+			if signing.RequireSigned {
+				return ErrManifestNotSigned
+			}
+			log.Debug(ctx, "Manifest is not signed", "key", key)
+			return nil
+		}
+		return fmt.Errorf("cannot get signature: %w", err)
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var signature manifestSignature
+	err = yaml.NewDecoder(body).Decode(&signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	err = body.Close()
+	if err != nil {
+		return fmt.Errorf("cannot close object: %w", err)
+	}
+
+	return verifyManifestSignature(signing.TrustedKeys, key, manifest, signature)
+}
+
+// manifestSignatureKey returns the key of the detached signature of a manifest with a given content.
+func manifestSignatureKey(key string, manifest []byte) string {
+	sum := sha256.Sum256(manifest)
+	return key + "." + hex.EncodeToString(sum[:]) + ManifestSignatureSuffix
+}
+
+// listManifestSignatures returns the keys of all detached signatures stored for a manifest.
+func listManifestSignatures(ctx context.Context, source ArtifactSource, key string) ([]string, error) {
+	objects, err := source.ListObjects(ctx, key+".")
+	if err != nil {
+		return nil, fmt.Errorf("cannot list signatures: %w", err)
+	}
+
+	var keys []string
+	for _, object := range objects {
+		digest, ok := strings.CutSuffix(strings.TrimPrefix(object.Key, key+"."), ManifestSignatureSuffix)
+		if !ok || len(digest) != hex.EncodedLen(sha256.Size) || strings.Contains(digest, "/") {
+			continue
+		}
+		keys = append(keys, object.Key)
+	}
+
+	return keys, nil
+}
+
+// putManifestSignature stores the detached signature of a manifest if the artifact source has a signing key. It must be called before
+// the manifest is stored, so that a manifest is never stored without its signature.
+func putManifestSignature(ctx context.Context, source ArtifactSource, key string, manifest []byte) error {
+	signing, ok := manifestSigningOf(source)
+	if !ok || signing.Signer == nil {
+		return nil
+	}
+
+	signature, err := signManifest(signing.Signer, key, manifest)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = yaml.NewEncoder(&buf).Encode(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	//nolint:wrapcheck // Directly wraps the source.
+	return source.PutObject(ctx, manifestSignatureKey(key, manifest), &buf)
+}
+
+// deleteStaleManifestSignatures deletes the signatures of earlier versions of a manifest after it has been stored. Since they do not
+// match the current content, failing to delete them is harmless and only logged.
+func deleteStaleManifestSignatures(ctx context.Context, source ArtifactSource, key string, manifest []byte) {
+	signing, ok := manifestSigningOf(source)
+	if !ok || (signing.Signer == nil && len(signing.TrustedKeys) == 0) {
+		return
+	}
+
+	keys, err := listManifestSignatures(ctx, source, key)
+	if err != nil {
+		log.Error(ctx, err, "key", key)
+		return
+	}
+
+	current := manifestSignatureKey(key, manifest)
+	for _, sigKey := range keys {
+		if sigKey == current {
+			continue
+		}
+		err = source.DeleteObject(ctx, sigKey)
+		if err != nil && !errors.As(err, &KeyNotFoundError{}) {
+			log.Error(ctx, fmt.Errorf("cannot delete stale signature: %w", err), "key", sigKey)
+		}
+	}
+}
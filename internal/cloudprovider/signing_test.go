@@ -0,0 +1,302 @@
+package cloudprovider_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+)
+
+// memSource is an artifact source which keeps its objects in memory. Storing the object with the key failPut fails.
+type memSource struct {
+	cloudprovider.ArtifactSource
+	objects map[string][]byte
+	failPut string
+}
+
+func newMemSource() *memSource {
+	return &memSource{
+		objects: make(map[string][]byte),
+	}
+}
+
+func (s *memSource) GetObject(_ context.Context, key string) (io.ReadCloser, error) {
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, cloudprovider.NewKeyNotFoundError(fmt.Errorf("object %s not found", key))
+	}
+
+	return io.NopCloser(bytes.NewReader(object)), nil
+}
+
+func (s *memSource) PutObject(_ context.Context, key string, object io.Reader) error {
+	if key == s.failPut {
+		return fmt.Errorf("cannot put object %s", key)
+	}
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return fmt.Errorf("cannot read object: %w", err)
+	}
+	s.objects[key] = data
+
+	return nil
+}
+
+func (s *memSource) ListObjects(_ context.Context, prefix string) ([]cloudprovider.ObjectInfo, error) {
+	var objects []cloudprovider.ObjectInfo
+	for _, key := range slices.Sorted(maps.Keys(s.objects)) {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, cloudprovider.ObjectInfo{
+				Key:  key,
+				Size: int64(len(s.objects[key])),
+			})
+		}
+	}
+
+	return objects, nil
+}
+
+func (s *memSource) DeleteObject(_ context.Context, key string) error {
+	_, ok := s.objects[key]
+	if !ok {
+		return cloudprovider.NewKeyNotFoundError(fmt.Errorf("object %s not found", key))
+	}
+	delete(s.objects, key)
+
+	return nil
+}
+
+func (s *memSource) CopyObject(_ context.Context, fromKey, toKey string) error {
+	object, ok := s.objects[fromKey]
+	if !ok {
+		return cloudprovider.NewKeyNotFoundError(fmt.Errorf("object %s not found", fromKey))
+	}
+	s.objects[toKey] = bytes.Clone(object)
+
+	return nil
+}
+
+func (s *memSource) signatures() []string {
+	var keys []string
+	for key := range s.objects {
+		if cloudprovider.IsManifestSignature(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+var _ = Describe("Manifest signing", func() {
+	const key = "meta/singles/aws-gardener_prod-amd64-1877.0-abcdef01"
+
+	var (
+		ctx       context.Context
+		store     *memSource
+		oldSigner crypto.Signer
+		newSigner crypto.Signer
+		manifest  *gl.Manifest
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = newMemSource()
+
+		var err error
+		_, oldSigner, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		newSigner, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		manifest = &gl.Manifest{
+			Version:         "1877.0",
+			BuildCommittish: "abcdef01",
+			Architecture:    gl.ArchitectureAMD64,
+			Platform:        "aws",
+			PublishedImageMetadata: &gl.PublishedImageMetadata{
+				AWSImages: []gl.AWSImage{
+					{
+						Cloud:  "public",
+						Region: "eu-central-1",
+						ID:     "ami-123",
+						Image:  "gardenlinux-aws-gardener_prod-amd64-1877.0-abcdef01",
+					},
+				},
+			},
+		}
+	})
+
+	signed := func(signing cloudprovider.ManifestSigning) cloudprovider.ArtifactSource {
+		return cloudprovider.WithManifestSigning(store, signing)
+	}
+
+	It("verifies a manifest it has signed", func() {
+		source := signed(cloudprovider.ManifestSigning{
+			Signer:        oldSigner,
+			TrustedKeys:   []crypto.PublicKey{oldSigner.Public()},
+			RequireSigned: true,
+		})
+		Expect(cloudprovider.PutManifest(ctx, source, key, manifest)).To(Succeed())
+		Expect(store.signatures()).To(HaveLen(1))
+
+		m, err := cloudprovider.GetManifest(ctx, source, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.Version).To(Equal(manifest.Version))
+		Expect(m.SchemaVersion).To(Equal(gl.ManifestSchemaVersion))
+		Expect(m.PublishedImageMetadata.AWSImages).To(Equal(manifest.PublishedImageMetadata.AWSImages))
+	})
+
+	It("rejects a tampered manifest", func() {
+		source := signed(cloudprovider.ManifestSigning{
+			Signer:        oldSigner,
+			TrustedKeys:   []crypto.PublicKey{oldSigner.Public()},
+			RequireSigned: true,
+		})
+		Expect(cloudprovider.PutManifest(ctx, source, key, manifest)).To(Succeed())
+		signature := store.objects[store.signatures()[0]]
+
+		tampered := bytes.Replace(store.objects[key], []byte("ami-123"), []byte("ami-666"), 1)
+		store.objects[key] = tampered
+		_, err := cloudprovider.GetManifest(ctx, source, key)
+		Expect(err).To(MatchError(ContainSubstring("not signed")))
+
+		sum := sha256.Sum256(tampered)
+		store.objects[key+"."+hex.EncodeToString(sum[:])+cloudprovider.ManifestSignatureSuffix] = signature
+		_, err = cloudprovider.GetManifest(ctx, source, key)
+		Expect(err).To(MatchError(ContainSubstring("signature does not match")))
+	})
+
+	It("rejects a manifest replayed under the key of another manifest", func() {
+		const otherKey = "meta/singles/gcp-gardener_prod-amd64-1877.0-abcdef01"
+
+		source := signed(cloudprovider.ManifestSigning{
+			Signer:        oldSigner,
+			TrustedKeys:   []crypto.PublicKey{oldSigner.Public()},
+			RequireSigned: true,
+		})
+		Expect(cloudprovider.PutManifest(ctx, source, key, manifest)).To(Succeed())
+		sigKey := store.signatures()[0]
+
+		store.objects[otherKey] = store.objects[key]
+		store.objects[otherKey+strings.TrimPrefix(sigKey, key)] = store.objects[sigKey]
+		_, err := cloudprovider.GetManifest(ctx, source, otherKey)
+		Expect(err).To(MatchError(ContainSubstring("signature does not match")))
+	})
+
+	It("signs a copied manifest anew", func() {
+		const backupKey = "meta/backups/aws-gardener_prod-amd64-1877.0-abcdef01"
+
+		source := signed(cloudprovider.ManifestSigning{
+			Signer:        oldSigner,
+			TrustedKeys:   []crypto.PublicKey{oldSigner.Public()},
+			RequireSigned: true,
+		})
+		Expect(cloudprovider.PutManifest(ctx, source, key, manifest)).To(Succeed())
+		Expect(cloudprovider.CopyManifest(ctx, source, key, backupKey)).To(Succeed())
+
+		m, err := cloudprovider.GetManifest(ctx, source, backupKey)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.Version).To(Equal(manifest.Version))
+	})
+
+	It("rejects a manifest signed by an untrusted key", func() {
+		Expect(cloudprovider.PutManifest(ctx, signed(cloudprovider.ManifestSigning{
+			Signer: oldSigner,
+		}), key, manifest)).To(Succeed())
+
+		_, err := cloudprovider.GetManifest(ctx, signed(cloudprovider.ManifestSigning{
+			TrustedKeys: []crypto.PublicKey{newSigner.Public()},
+		}), key)
+		Expect(err).To(MatchError(ContainSubstring("untrusted key")))
+	})
+
+	It("accepts manifests signed with a rotated key while it is still trusted", func() {
+		Expect(cloudprovider.PutManifest(ctx, signed(cloudprovider.ManifestSigning{
+			Signer: oldSigner,
+		}), key, manifest)).To(Succeed())
+
+		source := signed(cloudprovider.ManifestSigning{
+			Signer:        newSigner,
+			TrustedKeys:   []crypto.PublicKey{newSigner.Public(), oldSigner.Public()},
+			RequireSigned: true,
+		})
+		m, err := cloudprovider.GetManifest(ctx, source, key)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cloudprovider.PutManifest(ctx, source, key, m)).To(Succeed())
+		Expect(store.signatures()).To(HaveLen(1))
+
+		_, err = cloudprovider.GetManifest(ctx, signed(cloudprovider.ManifestSigning{
+			TrustedKeys:   []crypto.PublicKey{newSigner.Public()},
+			RequireSigned: true,
+		}), key)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("keeps the stored manifest valid if storing a new version fails after its signature has been written", func() {
+		source := signed(cloudprovider.ManifestSigning{
+			Signer:        oldSigner,
+			TrustedKeys:   []crypto.PublicKey{oldSigner.Public()},
+			RequireSigned: true,
+		})
+		Expect(cloudprovider.PutManifest(ctx, source, key, manifest)).To(Succeed())
+		stored := store.objects[key]
+
+		store.failPut = key
+		manifest.PublishedImageMetadata.AWSImages[0].ID = "ami-456"
+		Expect(cloudprovider.PutManifest(ctx, source, key, manifest)).NotTo(Succeed())
+		Expect(store.objects[key]).To(Equal(stored))
+		Expect(store.signatures()).To(HaveLen(2))
+
+		m, err := cloudprovider.GetManifest(ctx, source, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.PublishedImageMetadata.AWSImages[0].ID).To(Equal("ami-123"))
+
+		store.failPut = ""
+		Expect(cloudprovider.PutManifest(ctx, source, key, manifest)).To(Succeed())
+		Expect(store.signatures()).To(HaveLen(1))
+		m, err = cloudprovider.GetManifest(ctx, source, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.PublishedImageMetadata.AWSImages[0].ID).To(Equal("ami-456"))
+	})
+
+	It("requires signatures only on manifests written by GLCI", func() {
+		source := signed(cloudprovider.ManifestSigning{
+			TrustedKeys:   []crypto.PublicKey{oldSigner.Public()},
+			RequireSigned: true,
+		})
+		store.objects[key] = []byte("version: \"1877.0\"\nbuild_committish: abcdef01\narchitecture: amd64\nplatform: aws\n")
+		_, err := cloudprovider.GetManifest(ctx, source, key)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cloudprovider.PutManifest(ctx, store, key, manifest)).To(Succeed())
+		_, err = cloudprovider.GetManifest(ctx, source, key)
+		Expect(err).To(MatchError(ContainSubstring("not signed")))
+	})
+
+	It("deletes a manifest along with its signatures", func() {
+		source := signed(cloudprovider.ManifestSigning{
+			Signer: oldSigner,
+		})
+		Expect(cloudprovider.PutManifest(ctx, source, key, manifest)).To(Succeed())
+
+		Expect(cloudprovider.DeleteManifest(ctx, source, key)).To(Succeed())
+		Expect(store.objects).To(BeEmpty())
+	})
+})
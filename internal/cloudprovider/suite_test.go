@@ -10,4 +10,5 @@ import (
 func TestCloudProvider(t *testing.T) {
 	RegisterFailHandler(Fail)
 	t.Parallel()
+	RunSpecs(t, "CloudProvider Suite")
 }
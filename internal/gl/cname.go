@@ -0,0 +1,94 @@
+package gl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Cname is the canonical name of a Garden Linux flavor, such as aws-gardener_prod_tpm2_trustedboot-arm64. It consists of the platform, the
+// features and the architecture separated by dashes. Features are elements, which are separated by dashes, and flags, which start with an
+// underscore and are appended to the preceding element.
+type Cname struct {
+	Platform     string
+	Features     []string
+	Architecture Architecture
+}
+
+// ParseCname splits a cname into its platform, features and architecture. Flags are returned with their leading underscore.
+func ParseCname(cname string) (Cname, error) {
+	parts := strings.Split(cname, "-")
+	if len(parts) < 2 {
+		return Cname{}, fmt.Errorf("invalid cname %s: missing architecture", cname)
+	}
+
+	c := Cname{
+		Architecture: Architecture(parts[len(parts)-1]),
+	}
+	if c.Architecture != ArchitectureAMD64 && c.Architecture != ArchitectureARM64 {
+		return Cname{}, fmt.Errorf("invalid cname %s: unknown architecture %s", cname, c.Architecture)
+	}
+
+	for i, part := range parts[:len(parts)-1] {
+		element, flags, hasFlags := strings.Cut(part, "_")
+		if element == "" {
+			return Cname{}, fmt.Errorf("invalid cname %s: empty element", cname)
+		}
+		if i == 0 {
+			c.Platform = element
+		} else {
+			c.Features = append(c.Features, element)
+		}
+		if !hasFlags {
+			continue
+		}
+		for flag := range strings.SplitSeq(flags, "_") {
+			if flag == "" {
+				return Cname{}, fmt.Errorf("invalid cname %s: empty flag", cname)
+			}
+			c.Features = append(c.Features, "_"+flag)
+		}
+	}
+
+	return c, nil
+}
+
+// String returns the cname.
+func (c Cname) String() string {
+	var b strings.Builder
+	b.WriteString(c.Platform)
+	for _, feature := range c.Features {
+		if !strings.HasPrefix(feature, "_") {
+			b.WriteString("-")
+		}
+		b.WriteString(feature)
+	}
+	b.WriteString("-")
+	b.WriteString(string(c.Architecture))
+
+	return b.String()
+}
+
+// CheckManifest checks that the platform, architecture and modifiers of a manifest agree with the cname. The features of the cname are
+// only checked if the manifest lists modifiers, which are matched with or without the leading underscore of flags.
+func (c Cname) CheckManifest(m *Manifest) error {
+	var errs []error
+	if m.Platform != c.Platform {
+		errs = append(errs, fmt.Errorf("platform %s contradicts cname platform %s", m.Platform, c.Platform))
+	}
+	if m.Architecture != c.Architecture {
+		errs = append(errs, fmt.Errorf("architecture %s contradicts cname architecture %s", m.Architecture, c.Architecture))
+	}
+	if len(m.Modifiers) > 0 {
+		for _, feature := range c.Features {
+			if !slices.ContainsFunc(m.Modifiers, func(modifier string) bool {
+				return strings.TrimPrefix(modifier, "_") == strings.TrimPrefix(feature, "_")
+			}) {
+				errs = append(errs, fmt.Errorf("modifiers lack cname feature %s", feature))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
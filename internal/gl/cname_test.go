@@ -0,0 +1,51 @@
+package gl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardenlinux/glci/internal/gl"
+)
+
+var _ = Describe("Cname", func() {
+	DescribeTable("parses a cname into its elements and back",
+		func(cname string, expected gl.Cname) {
+			c, err := gl.ParseCname(cname)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).To(Equal(expected))
+			Expect(c.String()).To(Equal(cname))
+		},
+		Entry("platform only", "aws-amd64", gl.Cname{
+			Platform:     "aws",
+			Architecture: gl.ArchitectureAMD64,
+		}),
+		Entry("features", "gcp-gardener-arm64", gl.Cname{
+			Platform:     "gcp",
+			Features:     []string{"gardener"},
+			Architecture: gl.ArchitectureARM64,
+		}),
+		Entry("flags", "aws-gardener_prod_trustedboot-amd64", gl.Cname{
+			Platform:     "aws",
+			Features:     []string{"gardener", "_prod", "_trustedboot"},
+			Architecture: gl.ArchitectureAMD64,
+		}),
+		Entry("flags on the platform", "metal_pxe-amd64", gl.Cname{
+			Platform:     "metal",
+			Features:     []string{"_pxe"},
+			Architecture: gl.ArchitectureAMD64,
+		}),
+	)
+
+	DescribeTable("rejects an invalid cname",
+		func(cname, reason string) {
+			_, err := gl.ParseCname(cname)
+			Expect(err).To(MatchError(ContainSubstring(reason)))
+		},
+		Entry("without architecture", "aws", "missing architecture"),
+		Entry("with an unknown architecture", "aws-gardener-riscv64", "unknown architecture"),
+		Entry("with an empty platform", "-amd64", "empty element"),
+		Entry("with an empty feature", "aws--amd64", "empty element"),
+		Entry("with an empty flag", "aws-gardener__prod-amd64", "empty flag"),
+		Entry("with a trailing underscore", "aws-gardener_-amd64", "empty flag"),
+	)
+})
@@ -2,6 +2,9 @@ package gl
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"time"
 )
 
 const (
@@ -11,19 +14,22 @@ const (
 
 // Manifest is a release manifest generated by the Garden Linux build system and possibly modified by GLCI.
 type Manifest struct {
-	Version                string          `yaml:"version"`
-	BuildCommittish        string          `yaml:"build_committish"`
-	GLCIVersion            *string         `yaml:"glci_version,omitempty"`
-	Architecture           Architecture    `yaml:"architecture"`
-	Platform               string          `yaml:"platform"`
-	Modifiers              []string        `yaml:"modifiers"`
-	BuildTimestamp         string          `yaml:"build_timestamp"`
-	Paths                  []S3ReleaseFile `yaml:"paths"`
-	RequireUEFI            *bool           `yaml:"require_uefi,omitempty"`
-	SecureBoot             *bool           `yaml:"secureboot,omitempty"`
-	PublishedImageMetadata any             `yaml:"published_image_metadata"`
-	S3Bucket               string          `yaml:"s3_bucket"`
-	Unknown                map[string]any  `yaml:"-,inline,remain"`
+	SchemaVersion          int                     `yaml:"schema_version,omitempty"`
+	Version                string                  `yaml:"version"`
+	BuildCommittish        string                  `yaml:"build_committish"`
+	GLCIVersion            *string                 `yaml:"glci_version,omitempty"`
+	Architecture           Architecture            `yaml:"architecture"`
+	Platform               string                  `yaml:"platform"`
+	Modifiers              []string                `yaml:"modifiers"`
+	BuildTimestamp         string                  `yaml:"build_timestamp"`
+	Paths                  []S3ReleaseFile         `yaml:"paths"`
+	RequireUEFI            *bool                   `yaml:"require_uefi,omitempty"`
+	SecureBoot             *bool                   `yaml:"secureboot,omitempty"`
+	PublishedImageMetadata *PublishedImageMetadata `yaml:"published_image_metadata"`
+	PublishedAt            *time.Time              `yaml:"published_at,omitempty"`
+	IncompletePublications []string                `yaml:"incomplete_publications,omitempty"`
+	S3Bucket               string                  `yaml:"s3_bucket"`
+	Unknown                map[string]any          `yaml:"-,inline,remain"`
 }
 
 // Architecture is a CPU architecture.
@@ -56,3 +62,19 @@ func (m *Manifest) PathBySuffix(suffix string) (S3ReleaseFile, error) {
 
 	return S3ReleaseFile{}, fmt.Errorf("path for suffix %s missing in release manifest", suffix)
 }
+
+// Clone returns a copy of the manifest which can be modified without affecting the original.
+func (m *Manifest) Clone() *Manifest {
+	if m == nil {
+		return nil
+	}
+
+	c := *m
+	c.Modifiers = slices.Clone(m.Modifiers)
+	c.Paths = slices.Clone(m.Paths)
+	c.PublishedImageMetadata = m.PublishedImageMetadata.Clone()
+	c.IncompletePublications = slices.Clone(m.IncompletePublications)
+	c.Unknown = maps.Clone(m.Unknown)
+
+	return &c
+}
@@ -0,0 +1,19 @@
+package gl
+
+// ReleaseIndex aggregates the manifests of all flavors of a release, so that consumers can find every image of a release in one place.
+type ReleaseIndex struct {
+	Version         string               `yaml:"version"`
+	BuildCommittish string               `yaml:"build_committish"`
+	GLCIVersion     *string              `yaml:"glci_version,omitempty"`
+	Flavors         []ReleaseIndexFlavor `yaml:"flavors"`
+}
+
+// ReleaseIndexFlavor is a flavor of a release as recorded in its manifest.
+type ReleaseIndexFlavor struct {
+	Cname                  string                  `yaml:"cname"`
+	Architecture           Architecture            `yaml:"architecture"`
+	Platform               string                  `yaml:"platform"`
+	Modifiers              []string                `yaml:"modifiers"`
+	Paths                  []S3ReleaseFile         `yaml:"paths"`
+	PublishedImageMetadata *PublishedImageMetadata `yaml:"published_image_metadata,omitempty"`
+}
@@ -0,0 +1,77 @@
+package gl
+
+import (
+	"fmt"
+)
+
+// ManifestSchemaVersion is the version of the schema of manifests migrated by this version of GLCI. Manifests without a version have been
+// written by the build system or by earlier versions of GLCI.
+const ManifestSchemaVersion = 2
+
+// ManifestMigration upgrades a manifest from the previous schema version to Version.
+type ManifestMigration struct {
+	Version     int
+	Description string
+	migrate     func(m *Manifest)
+}
+
+// ManifestMigrations returns all migrations ordered by the schema version they upgrade to.
+func ManifestMigrations() []ManifestMigration {
+	return []ManifestMigration{
+		{
+			Version:     1,
+			Description: "normalize published image metadata",
+			migrate:     normalizePublishedImageMetadata,
+		},
+		{
+			Version:     2,
+			Description: "fill in require_uefi and secureboot defaults",
+			migrate:     fillInBootDefaults,
+		},
+	}
+}
+
+// Migrate applies all migrations which are newer than the schema version of the manifest in order and returns the applied ones.
+func (m *Manifest) Migrate() ([]ManifestMigration, error) {
+	if m.SchemaVersion < 0 || m.SchemaVersion > ManifestSchemaVersion {
+		return nil, fmt.Errorf("unsupported manifest schema version %d", m.SchemaVersion)
+	}
+
+	var applied []ManifestMigration
+	for _, migration := range ManifestMigrations() {
+		if migration.Version <= m.SchemaVersion {
+			continue
+		}
+
+		migration.migrate(m)
+		m.SchemaVersion = migration.Version
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+// normalizePublishedImageMetadata drops empty metadata and assigns AWS images recorded before GLCI distinguished AWS clouds to the
+// public cloud.
+func normalizePublishedImageMetadata(m *Manifest) {
+	if m.PublishedImageMetadata.IsEmpty() {
+		m.PublishedImageMetadata = nil
+		return
+	}
+
+	for i, img := range m.PublishedImageMetadata.AWSImages {
+		if img.Cloud == "" {
+			m.PublishedImageMetadata.AWSImages[i].Cloud = "public"
+		}
+	}
+}
+
+// fillInBootDefaults records the defaults which publishing targets assume for manifests that do not specify boot requirements.
+func fillInBootDefaults(m *Manifest) {
+	if m.RequireUEFI == nil {
+		m.RequireUEFI = new(bool)
+	}
+	if m.SecureBoot == nil {
+		m.SecureBoot = new(bool)
+	}
+}
@@ -0,0 +1,104 @@
+package gl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardenlinux/glci/internal/gl"
+)
+
+var _ = Describe("Manifest migration", func() {
+	versions := func(migrations []gl.ManifestMigration) []int {
+		v := make([]int, 0, len(migrations))
+		for _, migration := range migrations {
+			v = append(v, migration.Version)
+		}
+		return v
+	}
+
+	It("orders the migrations by consecutive schema versions up to the current one", func() {
+		migrations := gl.ManifestMigrations()
+		Expect(migrations).NotTo(BeEmpty())
+		for i, migration := range migrations {
+			Expect(migration.Version).To(Equal(i + 1))
+			Expect(migration.Description).NotTo(BeEmpty())
+		}
+		Expect(migrations[len(migrations)-1].Version).To(Equal(gl.ManifestSchemaVersion))
+	})
+
+	It("applies all migrations in order to an unversioned manifest", func() {
+		m := &gl.Manifest{
+			PublishedImageMetadata: &gl.PublishedImageMetadata{
+				AWSImages: []gl.AWSImage{
+					{
+						Region: "eu-central-1",
+						ID:     "ami-123",
+					},
+				},
+			},
+		}
+
+		applied, err := m.Migrate()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions(applied)).To(Equal(versions(gl.ManifestMigrations())))
+		Expect(m.SchemaVersion).To(Equal(gl.ManifestSchemaVersion))
+		Expect(m.PublishedImageMetadata.AWSImages[0].Cloud).To(Equal("public"))
+		Expect(m.RequireUEFI).To(HaveValue(BeFalse()))
+		Expect(m.SecureBoot).To(HaveValue(BeFalse()))
+	})
+
+	It("applies only the migrations newer than the schema version of the manifest", func() {
+		m := &gl.Manifest{
+			SchemaVersion: 1,
+			PublishedImageMetadata: &gl.PublishedImageMetadata{
+				AWSImages: []gl.AWSImage{
+					{
+						Region: "eu-central-1",
+						ID:     "ami-123",
+					},
+				},
+			},
+		}
+
+		applied, err := m.Migrate()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions(applied)).To(Equal([]int{2}))
+		Expect(m.SchemaVersion).To(Equal(gl.ManifestSchemaVersion))
+		Expect(m.PublishedImageMetadata.AWSImages[0].Cloud).To(BeEmpty())
+	})
+
+	It("leaves a current manifest unchanged", func() {
+		m := &gl.Manifest{
+			SchemaVersion: gl.ManifestSchemaVersion,
+		}
+
+		applied, err := m.Migrate()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(BeEmpty())
+		Expect(m.RequireUEFI).To(BeNil())
+	})
+
+	It("drops empty published image metadata", func() {
+		m := &gl.Manifest{
+			PublishedImageMetadata: &gl.PublishedImageMetadata{},
+		}
+
+		_, err := m.Migrate()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m.PublishedImageMetadata).To(BeNil())
+	})
+
+	DescribeTable("rejects an unsupported schema version",
+		func(version int) {
+			m := &gl.Manifest{
+				SchemaVersion: version,
+			}
+
+			_, err := m.Migrate()
+			Expect(err).To(MatchError(ContainSubstring("unsupported manifest schema version")))
+			Expect(m.SchemaVersion).To(Equal(version))
+		},
+		Entry("negative", -1),
+		Entry("newer than supported", gl.ManifestSchemaVersion+1),
+	)
+})
@@ -0,0 +1,117 @@
+package gl
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// PublishedImageMetadataSchemaVersion is the version of the schema of PublishedImageMetadata written by this version of GLCI. Metadata
+// without a version predates versioning and follows version 1.
+const PublishedImageMetadataSchemaVersion = 1
+
+// PublishedImageMetadata records the images published for a release flavor. It has a section per platform, named as in the untyped
+// metadata written by earlier versions of GLCI. Sections unknown to this version of GLCI are retained when a manifest is rewritten.
+type PublishedImageMetadata struct {
+	SchemaVersion   int              `yaml:"schema_version,omitempty"`
+	AWSImages       []AWSImage       `yaml:"published_aws_images,omitempty"`
+	AzureImages     []AzureImage     `yaml:"published_gallery_images,omitempty"`
+	GCPProject      string           `yaml:"gcp_project_name,omitempty"`
+	GCPImage        string           `yaml:"gcp_image_name,omitempty"`
+	OpenStackImages []OpenStackImage `yaml:"published_openstack_images,omitempty"`
+	AliyunImages    []AliyunImage    `yaml:"published_alicloud_images,omitempty"`
+	Unknown         map[string]any   `yaml:"-,inline,remain"`
+}
+
+// AWSImage is an AMI published to a region of an AWS cloud.
+type AWSImage struct {
+	Cloud  string `yaml:"cloud"`
+	Region string `yaml:"aws_region"`
+	ID     string `yaml:"ami_id"`
+	Image  string `yaml:"image_name"`
+}
+
+// AzureImage is a community gallery image version of a Hyper-V generation published to an Azure cloud.
+type AzureImage struct {
+	Cloud string `yaml:"azure_cloud"`
+	ID    string `yaml:"community_gallery_image_id"`
+	Gen   string `yaml:"hyper_v_generation"`
+}
+
+// OpenStackImage is an image published to a region of an OpenStack cloud for a hypervisor.
+type OpenStackImage struct {
+	Region     string `yaml:"region_name"`
+	ID         string `yaml:"image_id"`
+	Image      string `yaml:"image_name"`
+	Hypervisor string `yaml:"hypervisor"`
+}
+
+// AliyunImage is an image published to a region of Alibaba Cloud.
+type AliyunImage struct {
+	Region string `yaml:"region_id"`
+	ID     string `yaml:"image_id"`
+	Image  string `yaml:"image_name"`
+}
+
+// IsEmpty returns whether no images are recorded in the metadata.
+func (m *PublishedImageMetadata) IsEmpty() bool {
+	return m == nil || (len(m.AWSImages) == 0 && len(m.AzureImages) == 0 && m.GCPProject == "" && m.GCPImage == "" &&
+		len(m.OpenStackImages) == 0 && len(m.AliyunImages) == 0 && len(m.Unknown) == 0)
+}
+
+// Clone returns a copy of the metadata which can be modified without affecting the original.
+func (m *PublishedImageMetadata) Clone() *PublishedImageMetadata {
+	if m == nil {
+		return nil
+	}
+
+	c := *m
+	c.AWSImages = slices.Clone(m.AWSImages)
+	c.AzureImages = slices.Clone(m.AzureImages)
+	c.OpenStackImages = slices.Clone(m.OpenStackImages)
+	c.AliyunImages = slices.Clone(m.AliyunImages)
+	c.Unknown = maps.Clone(m.Unknown)
+
+	return &c
+}
+
+// Validate checks that the metadata follows a supported schema version and that all recorded images are complete.
+func (m *PublishedImageMetadata) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if m.SchemaVersion < 0 || m.SchemaVersion > PublishedImageMetadataSchemaVersion {
+		return fmt.Errorf("unsupported schema version %d", m.SchemaVersion)
+	}
+
+	for i, img := range m.AWSImages {
+		if img.Region == "" || img.ID == "" {
+			return fmt.Errorf("incomplete AWS image %d", i)
+		}
+	}
+	for i, img := range m.AzureImages {
+		if img.Cloud == "" || img.ID == "" {
+			return fmt.Errorf("incomplete Azure image %d", i)
+		}
+		if img.Gen != "V1" && img.Gen != "V2" {
+			return fmt.Errorf("invalid Hyper-V generation %s of Azure image %d", img.Gen, i)
+		}
+	}
+	if (m.GCPProject == "") != (m.GCPImage == "") {
+		return errors.New("incomplete GCP image")
+	}
+	for i, img := range m.OpenStackImages {
+		if img.Region == "" || img.ID == "" || img.Hypervisor == "" {
+			return fmt.Errorf("incomplete OpenStack image %d", i)
+		}
+	}
+	for i, img := range m.AliyunImages {
+		if img.Region == "" || img.ID == "" {
+			return fmt.Errorf("incomplete Aliyun image %d", i)
+		}
+	}
+
+	return nil
+}
@@ -10,4 +10,5 @@ import (
 func TestGL(t *testing.T) {
 	RegisterFailHandler(Fail)
 	t.Parallel()
+	RunSpecs(t, "GL Suite")
 }
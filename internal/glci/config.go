@@ -3,8 +3,12 @@ package glci
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"time"
 
 	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
 )
 
 // FlavorsConfig specifies what flavors of Garden Linux are to be worked on.
@@ -12,13 +16,24 @@ type FlavorsConfig struct {
 	Flavors []cfgFlavor `mapstructure:"flavors"`
 }
 
-// Validate ensures that the flavours configuration is valid.
+// Validate ensures that the flavours configuration is valid and that the cname of each flavor belongs to its platform.
 func (c *FlavorsConfig) Validate() error {
 	for _, flavor := range c.Flavors {
-		_, err := cloudprovider.NewPublishingTarget(flavor.Platform)
+		target, err := cloudprovider.NewPublishingTarget(flavor.Platform)
 		if err != nil {
 			return fmt.Errorf("invalid flavor %s: %w", flavor.Cname, err)
 		}
+
+		var cname gl.Cname
+		cname, err = gl.ParseCname(flavor.Cname)
+		if err != nil {
+			return fmt.Errorf("invalid flavor %s: %w", flavor.Cname, err)
+		}
+		platforms := target.Platforms()
+		if platforms != nil && !slices.Contains(platforms, cname.Platform) {
+			return fmt.Errorf("invalid flavor %s: platform %s does not publish cname platform %s", flavor.Cname, flavor.Platform,
+				cname.Platform)
+		}
 	}
 
 	return nil
@@ -26,11 +41,18 @@ func (c *FlavorsConfig) Validate() error {
 
 // PublishingConfig contains configuration for GLCI itself and for each cloud provider.
 type PublishingConfig struct {
-	ManifestSource string      `mapstructure:"manifest_source"`
-	ManifestTarget *string     `mapstructure:"manifest_target,omitempty"`
-	Sources        []cfgSource `mapstructure:"sources"`
-	Targets        []cfgTarget `mapstructure:"targets"`
-	OCM            cfgTarget   `mapstructure:"ocm"`
+	ManifestSource string         `mapstructure:"manifest_source"`
+	ManifestTarget *string        `mapstructure:"manifest_target,omitempty"`
+	Sources        []cfgSource    `mapstructure:"sources"`
+	Targets        []cfgTarget    `mapstructure:"targets"`
+	OCM            cfgTarget      `mapstructure:"ocm"`
+	Parallelism    map[string]int `mapstructure:"parallelism,omitempty"`
+	Retention      *cfgRetention  `mapstructure:"retention,omitempty"`
+	Retry          *cfgRetry      `mapstructure:"retry,omitempty"`
+	Polling        *cfgPolling    `mapstructure:"polling,omitempty"`
+	Hooks          []cfgHook      `mapstructure:"hooks,omitempty"`
+	// RequireSignedManifests rejects manifests written by GLCI without a valid signature by one of the trusted keys in the credentials.
+	RequireSignedManifests bool `mapstructure:"require_signed_manifests,omitempty"`
 }
 
 // Validate ensures that the publishing configuration is valid.
@@ -71,9 +93,71 @@ func (c *PublishingConfig) Validate() error {
 		return fmt.Errorf("invalid OCM target: %w", err)
 	}
 
+	for typ, limit := range c.Parallelism {
+		_, err = cloudprovider.NewPublishingTarget(typ)
+		if err != nil {
+			return fmt.Errorf("invalid parallelism: %w", err)
+		}
+		if limit < 1 {
+			return fmt.Errorf("invalid parallelism for %s: %d", typ, limit)
+		}
+	}
+
+	if c.Retention != nil {
+		err = c.Retention.validate()
+		if err != nil {
+			return fmt.Errorf("invalid retention policy: %w", err)
+		}
+	}
+
+	if c.Retry != nil {
+		err = c.Retry.validate()
+		if err != nil {
+			return fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
+
+	if c.Polling != nil {
+		err = c.Polling.validate()
+		if err != nil {
+			return fmt.Errorf("invalid polling policy: %w", err)
+		}
+	}
+
+	for i, hook := range c.Hooks {
+		err = hook.validate()
+		if err != nil {
+			return fmt.Errorf("invalid hook %d: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
+// retryPolicy returns the policy for retrying transient failures of cloud provider API calls, which is the default policy with any
+// configured overrides applied.
+func (c *PublishingConfig) retryPolicy() cloudprovider.RetryPolicy {
+	policy := cloudprovider.DefaultRetryPolicy
+	if c.Retry == nil {
+		return policy
+	}
+
+	if c.Retry.Attempts != nil {
+		policy.Attempts = *c.Retry.Attempts
+	}
+	if c.Retry.InitialBackoff != nil {
+		policy.InitialBackoff = *c.Retry.InitialBackoff
+	}
+	if c.Retry.MaxBackoff != nil {
+		policy.MaxBackoff = *c.Retry.MaxBackoff
+	}
+	if c.Retry.Timeout != nil {
+		policy.Timeout = *c.Retry.Timeout
+	}
+
+	return policy
+}
+
 // WithVersion stores the GLCI version string into the context.
 func WithVersion(ctx context.Context, version string) context.Context {
 	return context.WithValue(ctx, ctxkVer{}, version)
@@ -95,6 +179,113 @@ type cfgTarget struct {
 	Config map[string]any `mapstructure:"-,remain"`
 }
 
+// cfgRetention is a retention policy which determines the releases to be pruned. A release is kept if it is pinned, if it is one of the
+// newest KeepPatches patch versions of its minor version or if it has been published within KeepNewerThan. A release which has been
+// published longer ago than TTL is pruned unless it is pinned, regardless of the other rules.
+type cfgRetention struct {
+	KeepPatches   *int           `mapstructure:"keep_patches,omitempty"`
+	KeepNewerThan *time.Duration `mapstructure:"keep_newer_than,omitempty"`
+	Pinned        []string       `mapstructure:"pinned,omitempty"`
+	TTL           *time.Duration `mapstructure:"ttl,omitempty"`
+}
+
+func (c *cfgRetention) validate() error {
+	if c.KeepPatches != nil && *c.KeepPatches < 1 {
+		return fmt.Errorf("invalid number of patch versions to keep: %d", *c.KeepPatches)
+	}
+	if c.KeepNewerThan != nil && *c.KeepNewerThan <= 0 {
+		return fmt.Errorf("invalid minimum age: %s", *c.KeepNewerThan)
+	}
+	if c.TTL != nil && *c.TTL <= 0 {
+		return fmt.Errorf("invalid TTL: %s", *c.TTL)
+	}
+
+	return nil
+}
+
+// cfgRetry overrides the default policy for retrying transient failures of cloud provider API calls. Timeout limits every single attempt.
+type cfgRetry struct {
+	Attempts       *int           `mapstructure:"attempts,omitempty"`
+	InitialBackoff *time.Duration `mapstructure:"initial_backoff,omitempty"`
+	MaxBackoff     *time.Duration `mapstructure:"max_backoff,omitempty"`
+	Timeout        *time.Duration `mapstructure:"timeout,omitempty"`
+}
+
+func (c *cfgRetry) validate() error {
+	if c.Attempts != nil && *c.Attempts < 1 {
+		return fmt.Errorf("invalid number of attempts: %d", *c.Attempts)
+	}
+	if c.InitialBackoff != nil && *c.InitialBackoff <= 0 {
+		return fmt.Errorf("invalid initial backoff: %s", *c.InitialBackoff)
+	}
+	if c.MaxBackoff != nil && *c.MaxBackoff <= 0 {
+		return fmt.Errorf("invalid maximum backoff: %s", *c.MaxBackoff)
+	}
+	if c.Timeout != nil && *c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout: %s", *c.Timeout)
+	}
+
+	return nil
+}
+
+// pollPolicy returns the policy for polling long-running operations of cloud providers, which is the default policy with any configured
+// overrides applied.
+func (c *PublishingConfig) pollPolicy() cloudprovider.PollPolicy {
+	policy := cloudprovider.DefaultPollPolicy
+	policy.Deadlines = maps.Clone(policy.Deadlines)
+	if c.Polling == nil {
+		return policy
+	}
+
+	if c.Polling.Interval != nil {
+		policy.Interval = *c.Polling.Interval
+	}
+	if c.Polling.Backoff != nil {
+		policy.Backoff = *c.Polling.Backoff
+	}
+	if c.Polling.MaxInterval != nil {
+		policy.MaxInterval = *c.Polling.MaxInterval
+	}
+	for step, deadline := range c.Polling.Deadlines {
+		policy.Deadlines[cloudprovider.PollStep(step)] = deadline
+	}
+
+	return policy
+}
+
+// cfgPolling overrides the default policy for polling long-running operations of cloud providers. Deadlines limit the duration of the
+// import, copy and replication steps.
+type cfgPolling struct {
+	Interval    *time.Duration           `mapstructure:"interval,omitempty"`
+	Backoff     *float64                 `mapstructure:"backoff,omitempty"`
+	MaxInterval *time.Duration           `mapstructure:"max_interval,omitempty"`
+	Deadlines   map[string]time.Duration `mapstructure:"deadlines,omitempty"`
+}
+
+func (c *cfgPolling) validate() error {
+	if c.Interval != nil && *c.Interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", *c.Interval)
+	}
+	if c.Backoff != nil && *c.Backoff < 1 {
+		return fmt.Errorf("invalid backoff: %g", *c.Backoff)
+	}
+	if c.MaxInterval != nil && *c.MaxInterval <= 0 {
+		return fmt.Errorf("invalid maximum interval: %s", *c.MaxInterval)
+	}
+	for step, deadline := range c.Deadlines {
+		switch cloudprovider.PollStep(step) {
+		case cloudprovider.PollStepImport, cloudprovider.PollStepCopy, cloudprovider.PollStepReplication:
+		default:
+			return fmt.Errorf("invalid step %s", step)
+		}
+		if deadline <= 0 {
+			return fmt.Errorf("invalid deadline for %s: %s", step, deadline)
+		}
+	}
+
+	return nil
+}
+
 // AliasesConfig contains package aliases which are reflected in the component descriptor.
 type AliasesConfig map[string][]string
 
@@ -2,13 +2,17 @@ package glci
 
 import (
 	"context"
+	"crypto"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/goccy/go-yaml"
 
+	"github.com/gardenlinux/glci/internal/cloudprovider"
 	"github.com/gardenlinux/glci/internal/log"
 )
 
@@ -42,3 +46,51 @@ func LoadCredentials(ctx context.Context, credsFile, credsYamlBase64 string) (Cr
 
 	return creds, nil
 }
+
+type manifestSigningCredentials struct {
+	PrivateKey        string   `mapstructure:"private_key"`
+	TrustedPublicKeys []string `mapstructure:"trusted_public_keys"`
+}
+
+// manifestSigning loads the manifest signing policy from the manifest_signing section of the credentials. The public key of the signing
+// key is always trusted, further trusted keys allow verifying manifests signed by previous keys.
+func manifestSigning(creds Credentials, requireSigned bool) (cloudprovider.ManifestSigning, bool, error) {
+	signing := cloudprovider.ManifestSigning{
+		RequireSigned: requireSigned,
+	}
+
+	rawCreds, ok := creds["manifest_signing"]
+	if !ok {
+		if requireSigned {
+			return signing, false, errors.New("signed manifests are required but no trusted public keys are configured")
+		}
+		return signing, false, nil
+	}
+
+	var sCreds manifestSigningCredentials
+	err := mapstructure.Decode(rawCreds, &sCreds)
+	if err != nil {
+		return signing, false, fmt.Errorf("invalid manifest signing credentials: %w", err)
+	}
+
+	if sCreds.PrivateKey != "" {
+		signing.Signer, err = cloudprovider.ParseManifestSigningKey(sCreds.PrivateKey)
+		if err != nil {
+			return signing, false, fmt.Errorf("invalid manifest signing key: %w", err)
+		}
+		signing.TrustedKeys = append(signing.TrustedKeys, signing.Signer.Public())
+	}
+	for i, pemKey := range sCreds.TrustedPublicKeys {
+		var key crypto.PublicKey
+		key, err = cloudprovider.ParseManifestVerificationKey(pemKey)
+		if err != nil {
+			return signing, false, fmt.Errorf("invalid trusted public key %d: %w", i, err)
+		}
+		signing.TrustedKeys = append(signing.TrustedKeys, key)
+	}
+	if requireSigned && len(signing.TrustedKeys) == 0 {
+		return signing, false, errors.New("signed manifests are required but no trusted public keys are configured")
+	}
+
+	return signing, true, nil
+}
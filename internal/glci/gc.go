@@ -0,0 +1,164 @@
+package glci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// Orphan is a resource created by GLCI on a publishing target which no manifest references.
+type Orphan struct {
+	Target   string                     `json:"target"           yaml:"target"`
+	Kind     cloudprovider.ResourceKind `json:"kind"             yaml:"kind"`
+	Cloud    string                     `json:"cloud,omitempty"  yaml:"cloud,omitempty"`
+	Region   string                     `json:"region,omitempty" yaml:"region,omitempty"`
+	ID       string                     `json:"id"               yaml:"id"`
+	Name     string                     `json:"name,omitempty"   yaml:"name,omitempty"`
+	Created  time.Time                  `json:"created"          yaml:"created"`
+	Deleted  bool                       `json:"deleted"          yaml:"deleted"`
+	target   cloudprovider.PublishingTarget
+	resource cloudprovider.Resource
+}
+
+// GC finds resources on all publishing targets which have been created by GLCI but are not referenced by any manifest, such as
+// leftovers of failed or interrupted publications. Resources younger than minAge or of unknown age are ignored so that publications
+// which are in progress are never touched. Orphans are only deleted if confirm is set. All orphans are returned, even if some of them
+// could not be deleted.
+func GC(ctx context.Context, publishingConfig PublishingConfig, creds Credentials, minAge time.Duration, confirm bool) ([]Orphan,
+	error,
+) {
+	ctx = log.WithValues(ctx, "op", "gc")
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	manifestSource, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	// Publications in progress have not recorded their resources in any manifest yet.
+	if confirm {
+		var leases []Lease
+		leases, err = activeLeases(ctx, manifestTarget)
+		if err != nil {
+			return nil, err
+		}
+		if len(leases) > 0 {
+			return nil, fmt.Errorf("cannot delete orphaned resources while release %s-%.8s is locked by %s on %s", leases[0].Version,
+				leases[0].Commit, leases[0].Owner, leases[0].Hostname)
+		}
+	}
+
+	manifestSources := []cloudprovider.ArtifactSource{manifestSource}
+	if manifestTarget.Type() != manifestSource.Type() || manifestTarget.Repository() != manifestSource.Repository() {
+		manifestSources = append(manifestSources, manifestTarget)
+	}
+
+	// Images are collected per type of target so that no target considers the images of another target of the same type as orphans.
+	published := make(map[string][]cloudprovider.PublishedImage)
+	for _, source := range manifestSources {
+		err = collectPublishedImages(ctx, source, targets, published)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var orphans []Orphan
+	now := time.Now()
+	for _, target := range targets {
+		lctx := log.WithValues(ctx, "target", target.Type())
+
+		log.Info(lctx, "Looking for orphaned resources")
+		var resources []cloudprovider.Resource
+		resources, err = target.Orphans(lctx, published[target.Type()])
+		if err != nil {
+			return nil, fmt.Errorf("cannot find orphaned resources on %s: %w", target.Type(), err)
+		}
+
+		for _, resource := range resources {
+			if resource.Created.IsZero() || now.Sub(resource.Created) < minAge {
+				log.Debug(lctx, "Ignoring recent resource", "kind", resource.Kind, "id", resource.ID, "created", resource.Created)
+				continue
+			}
+			orphans = append(orphans, Orphan{
+				Target:   target.Type(),
+				Kind:     resource.Kind,
+				Cloud:    resource.Cloud,
+				Region:   resource.Region,
+				ID:       resource.ID,
+				Name:     resource.Name,
+				Created:  resource.Created,
+				target:   target,
+				resource: resource,
+			})
+		}
+	}
+	log.Info(ctx, "Found orphaned resources", "count", len(orphans))
+
+	var errs []error
+	if confirm {
+		for i, orphan := range orphans {
+			lctx := log.WithValues(ctx, "target", orphan.Target, "kind", orphan.Kind, "id", orphan.ID)
+
+			err = orphan.target.DeleteOrphan(lctx, orphan.resource)
+			if err != nil {
+				err = fmt.Errorf("cannot delete %s %s on %s: %w", orphan.Kind, orphan.ID, orphan.Target, err)
+				log.Error(lctx, err)
+				errs = append(errs, err)
+				continue
+			}
+			orphans[i].Deleted = true
+		}
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("cannot close sources and targets: %w", err))
+	}
+
+	return orphans, errors.Join(errs...)
+}
+
+func collectPublishedImages(ctx context.Context, source cloudprovider.ArtifactSource, targets []cloudprovider.PublishingTarget,
+	published map[string][]cloudprovider.PublishedImage,
+) error {
+	log.Debug(ctx, "Listing manifests", "source", source.Type(), "repository", source.Repository())
+	objects, err := source.ListObjects(ctx, manifestPrefix)
+	if err != nil {
+		return fmt.Errorf("cannot list manifests: %w", err)
+	}
+
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, "/") || cloudprovider.IsManifestSignature(object.Key) {
+			continue
+		}
+
+		// A manifest that cannot be read could reference any resource, so nothing can be considered orphaned.
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetManifest(ctx, source, object.Key)
+		if err != nil {
+			return fmt.Errorf("cannot get manifest %s: %w", object.Key, err)
+		}
+
+		for _, target := range targetsForPlatform(targets, manifest.Platform) {
+			var images []cloudprovider.PublishedImage
+			images, err = target.PublishedImages(manifest)
+			if err != nil {
+				return fmt.Errorf("cannot get published images of manifest %s: %w", object.Key, err)
+			}
+			published[target.Type()] = append(published[target.Type()], images...)
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gardenlinux/glci/internal/cloudprovider"
 	"github.com/gardenlinux/glci/internal/gl"
@@ -11,11 +12,39 @@ import (
 	"github.com/gardenlinux/glci/internal/ocm"
 )
 
+// Options control how a publish or remove operation is carried out.
+type Options struct {
+	// Parallelism is the maximum number of publications processed concurrently.
+	Parallelism int
+	// KeepGoing continues with the remaining publications after a publication has failed.
+	KeepGoing bool
+	// PartialDescriptor publishes a component descriptor containing the successful publications if some publications have failed.
+	PartialDescriptor bool
+	// KeepLeftovers keeps the resources created by a failed publication instead of rolling them back.
+	KeepLeftovers bool
+	// Selection narrows the operation to a subset of the release.
+	Selection Selection
+	// Report receives the outcome of every considered publication if set, also when the operation fails.
+	Report *Report
+}
+
 // Publish publishes a release to all cloud providers specified in the flavors and publishing configurations.
 func Publish(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig, aliasesConfig AliasesConfig,
-	creds Credentials, version, commit string,
+	creds Credentials, version, commit string, opts Options,
+) error {
+	return publishRelease(ctx, "publish", flavorsConfig, publishingConfig, aliasesConfig, creds, version, commit, opts, nil)
+}
+
+// publishRelease publishes a release, promoting the images from another publishing configuration if from is set.
+func publishRelease(ctx context.Context, op string, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig,
+	aliasesConfig AliasesConfig, creds Credentials, version, commit string, opts Options, from *promotionSource,
 ) error {
-	ctx = log.WithValues(ctx, "op", "publish", "version", version, "commit", commit)
+	ctx = log.WithValues(ctx, "op", op, "version", version, "commit", commit)
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+	ctx = cloudprovider.WithVerifiedFiles(ctx)
+	report := newReportBuilder(op, version)
+	defer report.finish(opts.Report)
 
 	log.Debug(ctx, "Loading credentials and configuration")
 	manifestSource, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
@@ -26,115 +55,232 @@ func Publish(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig
 		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
 	}()
 
-	publications := make([]cloudprovider.Publication, 0, len(flavorsConfig.Flavors)*2)
-	pubMap := make(map[string][]int, len(flavorsConfig.Flavors))
-	for _, flavor := range flavorsConfig.Flavors {
-		flavorPubs := pubMap[flavor.Cname]
-		found := false
+	selectedFlavorsConfig, selectedTargets, err := applySelection(ctx, opts.Selection, flavorsConfig, targets)
+	if err != nil {
+		return err
+	}
+
+	commit, err = resolveCommit(ctx, manifestSource, selectedFlavorsConfig, version, commit)
+	if err != nil {
+		return err
+	}
 
-		for _, target := range targets {
-			if target.Type() != flavor.Platform {
-				continue
-			}
-			found = true
-			manifestKey := fmt.Sprintf("meta/singles/%s-%s-%.8s", flavor.Cname, version, commit)
-			lctx := log.WithValues(ctx, "cname", flavor.Cname, "platform", flavor.Platform)
+	var lock *releaseLock
+	ctx, lock, err = acquireLock(ctx, manifestTarget, version, commit)
+	if err != nil {
+		return fmt.Errorf("cannot lock release: %w", err)
+	}
+	defer func() {
+		_ = lock.release(ctx)
+	}()
+
+	var plan []plannedPublication
+	plan, commit, err = planPublish(ctx, selectedFlavorsConfig, manifestSource, manifestTarget, selectedTargets, version, commit,
+		opts.Selection)
+	if err != nil {
+		return err
+	}
+	report.setCommit(commit)
+
+	publications := make([]cloudprovider.Publication, 0, len(plan))
+	for _, p := range plan {
+		switch p.reason {
+		case PlanReasonPublish, PlanReasonResume:
+			publications = append(publications, p.publication)
+		case PlanReasonPublished:
+			report.skipped(p.publication, p.reason)
+		case PlanReasonManifestMissing:
+			return fmt.Errorf("cannot get manifest for %s: %w", p.publication.Cname, p.err)
+		case PlanReasonNoTarget:
+			return fmt.Errorf("no publishing target for %s", p.publication.Cname)
+		default:
+			return fmt.Errorf("unexpected plan for %s: %s", p.publication.Cname, p.reason)
+		}
+	}
+
+	hooks := newReleaseHooks(publishingConfig.Hooks, op, version, commit)
+	err = hooks.preRelease(ctx, publications)
+	if err != nil {
+		return err
+	}
 
-			log.Info(lctx, "Retrieving manifest")
-			var manifest *gl.Manifest
-			manifest, err = cloudprovider.GetManifest(lctx, manifestSource, manifestKey)
+	// A selection only covers part of the release, the component descriptor has to describe all of it.
+	var descriptor *ocm.ComponentDescriptor
+	if opts.Selection.IsEmpty() {
+		descriptor, err = ocm.BuildComponentDescriptor(ctx, manifestSource, publications, ocmTarget, aliasesConfig, version, commit)
+		if err != nil {
+			return fmt.Errorf("cannot build component descriptor: %w", err)
+		}
+	}
+
+	if len(publications) > 0 {
+		log.Info(ctx, "Publishing images", "count", len(publications))
+	} else {
+		log.Info(ctx, "Nothing to publish")
+	}
+
+	locks, ownPublications := newManifestLocks(publications)
+	var results []error
+	results, err = runPublications(ctx, ownPublications, opts.Parallelism, publishingConfig.Parallelism, opts.KeepGoing,
+		hooks.failures(report.timed(func(ctx context.Context, publication cloudprovider.Publication) error {
+			lctx := log.WithValues(ctx, "cname", publication.Cname, "platform", publication.Target.Type())
+
+			journal, err := cloudprovider.LoadJournal(lctx, manifestTarget, journalKey(publication.Cname, version, commit,
+				publication.Target.Type()))
 			if err != nil {
-				return fmt.Errorf("cannot get manifest for %s: %w", flavor.Cname, err)
+				return fmt.Errorf("cannot load journal for %s: %w", publication.Cname, err)
 			}
-			if manifest.Version != version {
-				return fmt.Errorf("manifest for %s has incorrect version %s", flavor.Cname, manifest.Version)
+
+			log.Info(lctx, "Publishing image")
+			var output cloudprovider.PublishingOutput
+			var rollback cloudprovider.Rollback
+			jctx := cloudprovider.WithJournal(lctx, journal)
+			if !opts.KeepLeftovers {
+				jctx = cloudprovider.WithRollback(jctx, &rollback)
+			}
+			if from != nil {
+				output, err = from.promote(jctx, publication, version, commit, sources)
+			} else {
+				output, err = publication.Target.Publish(jctx, publication.Cname, publication.Manifest, sources)
 			}
-			if manifest.BuildCommittish != commit && fmt.Sprintf("%.8s", manifest.BuildCommittish) != commit {
-				return fmt.Errorf("manifest for %s has incorrect commit %s", flavor.Cname, manifest.BuildCommittish)
+			if err != nil {
+				err = fmt.Errorf("cannot publish %s to %s: %w", publication.Cname, publication.Target.Type(), err)
+				switch {
+				case opts.KeepLeftovers:
+					log.Info(lctx, "Keeping leftovers of failed publication")
+					return err
+				case ctx.Err() != nil || errors.Is(err, context.Canceled):
+					// The next run resumes an interrupted publication from its journal, which needs the resources created so far.
+					log.Info(lctx, "Keeping journal of interrupted publication")
+					return err
+				}
+				return errors.Join(err, rollBack(lctx, &rollback, journal))
 			}
-			commit = manifest.BuildCommittish
 
-			log.Debug(lctx, "Retrieving target manifest")
-			var targetManifest *gl.Manifest
-			targetManifest, err = cloudprovider.GetManifest(lctx, manifestTarget, manifestKey)
-			if err != nil && !errors.As(err, &cloudprovider.KeyNotFoundError{}) {
-				return fmt.Errorf("cannot get target manifest for %s: %w", flavor.Cname, err)
+			var metadata *gl.PublishedImageMetadata
+			metadata, err = recordPublication(lctx, locks, manifestTarget, publication, output,
+				manifestKey(publication.Cname, version, commit), len(opts.Selection.Regions) == 0)
+			if err != nil {
+				return err
 			}
-			if targetManifest != nil {
-				if targetManifest.Version != version {
-					return fmt.Errorf("target manifest for %s has incorrect version %s", flavor.Cname, targetManifest.Version)
-				}
-				if targetManifest.BuildCommittish != commit {
-					return fmt.Errorf("target manifest for %s has incorrect commit %s", flavor.Cname, targetManifest.BuildCommittish)
-				}
 
-				var isPublished bool
-				isPublished, err = target.IsPublished(targetManifest)
-				if err != nil {
-					return fmt.Errorf("cannot determine publishing status for %s: %w", flavor.Cname, err)
-				}
-				if isPublished {
-					log.Info(lctx, "Already published, skipping")
-					continue
-				}
+			log.Debug(lctx, "Clearing journal")
+			err = journal.Clear(lctx)
+			if err != nil {
+				return fmt.Errorf("cannot clear journal for %s: %w", publication.Cname, err)
 			}
 
-			publications = append(publications, cloudprovider.Publication{
-				Cname:    flavor.Cname,
-				Manifest: manifest,
-				Target:   target,
-			})
-			pubMap[flavor.Cname] = append(flavorPubs, len(publications)-1)
+			report.output(publication, output)
+			hooks.postPublication(lctx, publication, output, metadata)
+
+			return nil
+		})))
+	report.ran(publications, results, ReportOutcomePublished)
+	indexErr := updateReleaseIndex(ctx, manifestTarget, version, commit)
+	if indexErr != nil {
+		if err == nil {
+			return indexErr
+		}
+		log.Error(ctx, indexErr)
+	}
+	if err != nil && !opts.KeepGoing {
+		return err
+	}
+	if err != nil {
+		failed := summarizeFailures(ctx, "Publishing", publications, results)
+		if !opts.PartialDescriptor || descriptor == nil {
+			log.Info(ctx, "Holding back component descriptor")
+			return failed
 		}
 
-		if !found {
-			return fmt.Errorf("no publishing target for %s", flavor.Cname)
+		retain := make([]bool, len(publications))
+		for i := range publications {
+			retain[i] = results[i] == nil
+		}
+		publications, err = ocm.RetainPublications(descriptor, publications, retain)
+		if err != nil {
+			return errors.Join(failed, fmt.Errorf("cannot retain successful publications in component descriptor: %w", err))
 		}
+		if len(publications) == 0 {
+			log.Info(ctx, "No successful publications, holding back component descriptor")
+			return failed
+		}
+		err = publishComponentDescriptor(ctx, ocmTarget, descriptor, publications, version, hooks)
+		if err != nil {
+			return errors.Join(failed, err)
+		}
+
+		return failed
 	}
 
-	var descriptor *ocm.ComponentDescriptor
-	descriptor, err = ocm.BuildComponentDescriptor(ctx, manifestSource, publications, ocmTarget, aliasesConfig, version, commit)
+	if descriptor != nil {
+		err = publishComponentDescriptor(ctx, ocmTarget, descriptor, publications, version, hooks)
+	} else {
+		err = publishReleaseDescriptor(ctx, flavorsConfig, publishingConfig, aliasesConfig, creds, ocmTarget, version, commit, hooks)
+	}
 	if err != nil {
-		return fmt.Errorf("cannot build component descriptor: %w", err)
+		return err
 	}
 
-	if len(publications) > 0 {
-		log.Info(ctx, "Publishing images", "count", len(publications))
-	} else {
-		log.Info(ctx, "Nothing to publish")
+	err = lock.release(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot release lock: %w", err)
 	}
 
-	for i, publication := range publications {
-		lctx := log.WithValues(ctx, "cname", publication.Cname, "platform", publication.Target.Type())
+	log.Debug(ctx, "Closing sources and targets")
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return fmt.Errorf("cannot close sources and targets: %w", err)
+	}
 
-		log.Info(lctx, "Publishing image")
-		var output cloudprovider.PublishingOutput
-		output, err = publication.Target.Publish(lctx, publication.Cname, publication.Manifest, sources)
-		if err != nil {
-			return fmt.Errorf("cannot publish %s to %s: %w", publication.Cname, publication.Target.Type(), err)
-		}
+	log.Info(ctx, "Publishing completed successfully")
+	return nil
+}
 
-		manifestOutput := publication.Manifest.PublishedImageMetadata
-		manifestOutput, err = publications[i].Target.AddOwnPublishingOutput(manifestOutput, output)
-		if err != nil {
-			return fmt.Errorf("cannot add publishing output for %s: %w", publication.Cname, err)
-		}
-		publication.Manifest.PublishedImageMetadata = manifestOutput
-		glciVer := glciVersion(ctx)
-		if glciVer != "" {
-			publication.Manifest.GLCIVersion = &glciVer
-		}
+// publishReleaseDescriptor publishes a component descriptor of the whole release once every flavor has been published to every configured
+// publishing target. The configuration is loaded afresh since the targets used for publishing may have been narrowed by a selection.
+func publishReleaseDescriptor(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig,
+	aliasesConfig AliasesConfig, creds Credentials, ocmTarget cloudprovider.OCMTarget, version, commit string, hooks *releaseHooks,
+) error {
+	log.Debug(ctx, "Checking whether the release is completely published")
+	manifestSource, manifestTarget, sources, targets, releaseOCMTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, releaseOCMTarget)
+	}()
 
-		log.Info(lctx, "Updating manifest")
-		manifestKey := fmt.Sprintf("meta/singles/%s-%s-%.8s", publication.Cname, version, commit)
-		err = cloudprovider.PutManifest(lctx, manifestTarget, manifestKey, publication.Manifest)
-		if err != nil {
-			return fmt.Errorf("cannot put manifest for %s: %w", publication.Cname, err)
+	var plan []plannedPublication
+	plan, _, err = planPublish(ctx, flavorsConfig, manifestSource, manifestTarget, targets, version, commit, Selection{})
+	if err != nil {
+		return err
+	}
+
+	publications := make([]cloudprovider.Publication, 0, len(plan))
+	for _, p := range plan {
+		if p.reason != PlanReasonPublished {
+			log.Info(ctx, "Release not completely published, holding back component descriptor", "cname", p.publication.Cname,
+				"reason", p.reason)
+			return nil
 		}
+		publications = append(publications, p.publication)
 	}
 
+	var descriptor *ocm.ComponentDescriptor
+	descriptor, err = ocm.BuildComponentDescriptor(ctx, manifestSource, publications, ocmTarget, aliasesConfig, version, commit)
+	if err != nil {
+		return fmt.Errorf("cannot build component descriptor: %w", err)
+	}
+
+	return publishComponentDescriptor(ctx, ocmTarget, descriptor, publications, version, hooks)
+}
+
+func publishComponentDescriptor(ctx context.Context, ocmTarget cloudprovider.OCMTarget, descriptor *ocm.ComponentDescriptor,
+	publications []cloudprovider.Publication, version string, hooks *releaseHooks,
+) error {
 	log.Debug(ctx, "Finalizing component descriptor")
-	err = ocm.AddPublicationOutput(descriptor, publications)
+	err := ocm.AddPublicationOutput(descriptor, publications)
 	if err != nil {
 		return fmt.Errorf("cannot add publication output to component descriptor: %w", err)
 	}
@@ -150,21 +296,95 @@ func Publish(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig
 	if err != nil {
 		return fmt.Errorf("cannot publish component descriptor: %w", err)
 	}
+	hooks.postOCM(ctx, ocmTarget)
 
-	log.Debug(ctx, "Closing sources and targets")
-	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	return nil
+}
+
+// recordPublication adds the output of a publication to the manifest shared by all publications of its cname and stores the manifest. It
+// returns a copy of the published image metadata, so that it can be used once the manifest has been unlocked.
+func recordPublication(ctx context.Context, locks *manifestLocks, manifestTarget cloudprovider.ArtifactSource,
+	publication cloudprovider.Publication, output cloudprovider.PublishingOutput, key string, complete bool,
+) (*gl.PublishedImageMetadata, error) {
+	manifest, unlock := locks.lock(publication.Cname)
+	defer unlock()
+
+	manifestOutput, err := publication.Target.AddOwnPublishingOutput(manifest.PublishedImageMetadata, output)
 	if err != nil {
-		return fmt.Errorf("cannot close sources and targets: %w", err)
+		return nil, fmt.Errorf("cannot add publishing output for %s: %w", publication.Cname, err)
+	}
+	manifest.PublishedImageMetadata = manifestOutput
+	recordCompleteness(manifest, publication.Target, complete)
+	// The first publication of a manifest determines the age of its release, rewrites of the manifest must not reset it.
+	if manifest.PublishedAt == nil {
+		publishedAt := time.Now().UTC()
+		manifest.PublishedAt = &publishedAt
+	}
+	glciVer := glciVersion(ctx)
+	if glciVer != "" {
+		manifest.GLCIVersion = &glciVer
 	}
 
-	log.Info(ctx, "Publishing completed successfully")
-	return nil
+	log.Info(ctx, "Updating manifest")
+	err = cloudprovider.PutManifest(ctx, manifestTarget, key, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot put manifest for %s: %w", publication.Cname, err)
+	}
+
+	return manifest.PublishedImageMetadata.Clone(), nil
+}
+
+// recordRemoval removes the output of a publication from the manifest shared by all publications of its cname and stores the manifest.
+// It returns a copy of the published image metadata, so that it can be used once the manifest has been unlocked.
+func recordRemoval(ctx context.Context, locks *manifestLocks, manifestSource, manifestTarget cloudprovider.ArtifactSource,
+	publication cloudprovider.Publication, key string, complete bool,
+) (*gl.PublishedImageMetadata, error) {
+	manifest, unlock := locks.lock(publication.Cname)
+	defer unlock()
+
+	manifestOutput, err := publication.Target.RemoveOwnPublishingOutput(manifest.PublishedImageMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("cannot remove publishing output for %s: %w", publication.Cname, err)
+	}
+	manifest.PublishedImageMetadata = manifestOutput
+	recordCompleteness(manifest, publication.Target, complete)
+	if manifestOutput == nil {
+		manifest.PublishedAt = nil
+	}
+	glciVer := glciVersion(ctx)
+	if glciVer != "" {
+		manifest.GLCIVersion = &glciVer
+	}
+
+	// A separate manifest target only records publications, a manifest without any is stale.
+	if manifestTarget != manifestSource && manifestOutput == nil {
+		log.Info(ctx, "Deleting stale manifest")
+		err = cloudprovider.DeleteManifest(ctx, manifestTarget, key)
+		if err != nil {
+			return nil, fmt.Errorf("cannot delete manifest for %s: %w", publication.Cname, err)
+		}
+
+		return nil, nil
+	}
+
+	log.Info(ctx, "Updating manifest")
+	err = cloudprovider.PutManifest(ctx, manifestTarget, key, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot put manifest for %s: %w", publication.Cname, err)
+	}
+
+	return manifest.PublishedImageMetadata.Clone(), nil
 }
 
 // Remove removes a release from all cloud providers specified in the flavors and publishing configurations.
 func Remove(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig, creds Credentials, version, commit string,
+	opts Options,
 ) error {
 	ctx = log.WithValues(ctx, "op", "remove", "version", version, "commit", commit)
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+	report := newReportBuilder("remove", version)
+	defer report.finish(opts.Report)
 
 	log.Debug(ctx, "Loading credentials and configuration")
 	manifestSource, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
@@ -175,92 +395,104 @@ func Remove(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig P
 		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
 	}()
 
-	publications := make([]cloudprovider.Publication, 0, len(flavorsConfig.Flavors)*2)
-	pubMap := make(map[string][]int, len(flavorsConfig.Flavors))
-	for _, flavor := range flavorsConfig.Flavors {
-		flavorPubs := pubMap[flavor.Cname]
-		found := false
+	selectedFlavorsConfig, selectedTargets, err := applySelection(ctx, opts.Selection, flavorsConfig, targets)
+	if err != nil {
+		return err
+	}
 
-		for _, target := range targets {
-			if target.Type() != flavor.Platform {
-				continue
-			}
-			found = true
-			manifestKey := fmt.Sprintf("meta/singles/%s-%s-%.8s", flavor.Cname, version, commit)
-			lctx := log.WithValues(ctx, "cname", flavor.Cname, "platform", flavor.Platform)
+	commit, err = resolveCommit(ctx, manifestTarget, selectedFlavorsConfig, version, commit)
+	if err != nil {
+		return err
+	}
 
-			log.Info(lctx, "Retrieving manifest")
-			var manifest *gl.Manifest
-			manifest, err = cloudprovider.GetManifest(lctx, manifestTarget, manifestKey)
-			if err != nil {
-				if errors.As(err, &cloudprovider.KeyNotFoundError{}) && manifestTarget != manifestSource {
-					log.Debug(lctx, "Manifest not found, skipping")
-					continue
-				}
-				return fmt.Errorf("cannot get manifest for %s: %w", flavor.Cname, err)
-			}
-			if manifest.Version != version {
-				return fmt.Errorf("manifest for %s has incorrect version %s", flavor.Cname, manifest.Version)
-			}
-			if manifest.BuildCommittish != commit && fmt.Sprintf("%.8s", manifest.BuildCommittish) != commit {
-				return fmt.Errorf("manifest for %s has incorrect commit %s", flavor.Cname, manifest.BuildCommittish)
-			}
-			commit = manifest.BuildCommittish
+	var lock *releaseLock
+	ctx, lock, err = acquireLock(ctx, manifestTarget, version, commit)
+	if err != nil {
+		return fmt.Errorf("cannot lock release: %w", err)
+	}
+	defer func() {
+		_ = lock.release(ctx)
+	}()
 
-			var isPublished bool
-			isPublished, err = target.IsPublished(manifest)
-			if err != nil {
-				return fmt.Errorf("cannot determine publishing status for %s: %w", flavor.Cname, err)
-			}
-			if !isPublished {
-				log.Debug(lctx, "Already removed, skipping")
+	var plan []plannedPublication
+	plan, commit, err = planRemove(ctx, selectedFlavorsConfig, manifestTarget, selectedTargets, version, commit)
+	if err != nil {
+		return err
+	}
+	report.setCommit(commit)
+
+	publications := make([]cloudprovider.Publication, 0, len(plan))
+	for _, p := range plan {
+		switch p.reason {
+		case PlanReasonRemove:
+			publications = append(publications, p.publication)
+		case PlanReasonNotPublished:
+			report.skipped(p.publication, p.reason)
+		case PlanReasonManifestMissing:
+			if manifestTarget != manifestSource {
+				report.skipped(p.publication, p.reason)
 				continue
 			}
-
-			publications = append(publications, cloudprovider.Publication{
-				Cname:    flavor.Cname,
-				Manifest: manifest,
-				Target:   target,
-			})
-			pubMap[flavor.Cname] = append(flavorPubs, len(publications)-1)
-		}
-		if !found {
-			return fmt.Errorf("no publishing target for %s", flavor.Cname)
+			return fmt.Errorf("cannot get manifest for %s: %w", p.publication.Cname, p.err)
+		case PlanReasonNoTarget:
+			return fmt.Errorf("no publishing target for %s", p.publication.Cname)
+		default:
+			return fmt.Errorf("unexpected plan for %s: %s", p.publication.Cname, p.reason)
 		}
 	}
 
+	hooks := newReleaseHooks(publishingConfig.Hooks, "remove", version, commit)
+	err = hooks.preRelease(ctx, publications)
+	if err != nil {
+		return err
+	}
+
 	if len(publications) > 0 {
 		log.Info(ctx, "Removing images", "count", len(publications))
 	} else {
 		log.Info(ctx, "Nothing to remove")
 	}
 
-	for i, publication := range publications {
-		lctx := log.WithValues(ctx, "cname", publication.Cname, "platform", publication.Target.Type())
+	locks, ownPublications := newManifestLocks(publications)
+	var results []error
+	results, err = runPublications(ctx, ownPublications, opts.Parallelism, publishingConfig.Parallelism, opts.KeepGoing,
+		hooks.failures(report.timed(func(ctx context.Context, publication cloudprovider.Publication) error {
+			lctx := log.WithValues(ctx, "cname", publication.Cname, "platform", publication.Target.Type())
 
-		log.Info(lctx, "Removing image")
-		err = publication.Target.Remove(lctx, publication.Manifest, sources)
-		if err != nil {
-			return fmt.Errorf("cannot remove %s from %s: %w", publication.Cname, publication.Target.Type(), err)
-		}
+			log.Info(lctx, "Removing image")
+			err := publication.Target.Remove(lctx, publication.Manifest, sources)
+			if err != nil {
+				return fmt.Errorf("cannot remove %s from %s: %w", publication.Cname, publication.Target.Type(), err)
+			}
 
-		manifestOutput := publication.Manifest.PublishedImageMetadata
-		manifestOutput, err = publications[i].Target.RemoveOwnPublishingOutput(manifestOutput)
-		if err != nil {
-			return fmt.Errorf("cannot remove publishing output for %s: %w", publication.Cname, err)
-		}
-		publication.Manifest.PublishedImageMetadata = manifestOutput
-		glciVer := glciVersion(ctx)
-		if glciVer != "" {
-			publication.Manifest.GLCIVersion = &glciVer
+			var metadata *gl.PublishedImageMetadata
+			metadata, err = recordRemoval(lctx, locks, manifestSource, manifestTarget, publication,
+				manifestKey(publication.Cname, version, commit), len(opts.Selection.Regions) == 0)
+			if err != nil {
+				return err
+			}
+			hooks.postPublication(lctx, publication, nil, metadata)
+
+			return nil
+		})))
+	report.ran(publications, results, ReportOutcomeRemoved)
+	indexErr := updateReleaseIndex(ctx, manifestTarget, version, commit)
+	if indexErr != nil {
+		if err == nil {
+			return indexErr
 		}
+		log.Error(ctx, indexErr)
+	}
+	if err != nil && !opts.KeepGoing {
+		return err
+	}
+	if err != nil {
+		return summarizeFailures(ctx, "Removing", publications, results)
+	}
 
-		log.Info(lctx, "Updating manifest")
-		manifestKey := fmt.Sprintf("meta/singles/%s-%s-%.8s", publication.Cname, version, commit)
-		err = cloudprovider.PutManifest(lctx, manifestTarget, manifestKey, publication.Manifest)
-		if err != nil {
-			return fmt.Errorf("cannot put manifest for %s: %w", publication.Cname, err)
-		}
+	err = lock.release(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot release lock: %w", err)
 	}
 
 	log.Debug(ctx, "Closing sources and targets")
@@ -273,13 +505,52 @@ func Remove(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig P
 	return nil
 }
 
+// rollBack deletes the resources created by a failed publication. The journal of the publication is cleared as well since it would
+// otherwise refer to deleted resources.
+func rollBack(ctx context.Context, rollback *cloudprovider.Rollback, journal *cloudprovider.Journal) error {
+	log.Info(ctx, "Rolling back failed publication", "resources", rollback.Len())
+	err := rollback.Run(ctx)
+	if err != nil {
+		err = fmt.Errorf("incomplete rollback: %w", err)
+	}
+
+	clearErr := journal.Clear(context.WithoutCancel(ctx))
+	if clearErr != nil {
+		err = errors.Join(err, fmt.Errorf("cannot clear journal: %w", clearErr))
+	}
+
+	return err
+}
+
+// isPublishedAnywhere returns whether any image of a manifest is published to one of the publishing targets, even if a publication is not
+// complete.
+func isPublishedAnywhere(manifest *gl.Manifest, targets []cloudprovider.PublishingTarget) (bool, error) {
+	for _, target := range targetsForPlatform(targets, manifest.Platform) {
+		images, err := target.PublishedImages(manifest)
+		if err != nil {
+			return false, err //nolint:wrapcheck // Directly wraps the target.
+		}
+		if len(images) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func loadCredentialsAndConfig(ctx context.Context, creds Credentials, publishingConfig PublishingConfig) (cloudprovider.ArtifactSource,
 	cloudprovider.ArtifactSource, map[string]cloudprovider.ArtifactSource, []cloudprovider.PublishingTarget, cloudprovider.OCMTarget,
 	error,
 ) {
+	signing, signed, err := manifestSigning(creds, publishingConfig.RequireSignedManifests)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
 	sources := make(map[string]cloudprovider.ArtifactSource, len(publishingConfig.Sources))
 	for _, s := range publishingConfig.Sources {
-		source, err := cloudprovider.NewArtifactSource(s.Type)
+		var source cloudprovider.ArtifactSource
+		source, err = cloudprovider.NewArtifactSource(s.Type)
 		if err != nil {
 			return nil, nil, nil, nil, nil, fmt.Errorf("invalid artifact source %s: %w", s.ID, err)
 		}
@@ -299,10 +570,20 @@ func loadCredentialsAndConfig(ctx context.Context, creds Credentials, publishing
 	if publishingConfig.ManifestTarget != nil {
 		manifestTarget = sources[*publishingConfig.ManifestTarget]
 	}
+	// Only manifests written by GLCI are signed. A separate manifest source only holds the manifests of the build system, so its manifests
+	// are not verified.
+	if signed {
+		separate := manifestTarget != manifestSource
+		manifestTarget = cloudprovider.WithManifestSigning(manifestTarget, signing)
+		if !separate {
+			manifestSource = manifestTarget
+		}
+	}
 
 	targets := make([]cloudprovider.PublishingTarget, 0, len(publishingConfig.Targets))
 	for _, t := range publishingConfig.Targets {
-		target, err := cloudprovider.NewPublishingTarget(t.Type)
+		var target cloudprovider.PublishingTarget
+		target, err = cloudprovider.NewPublishingTarget(t.Type)
 		if err != nil {
 			return nil, nil, nil, nil, nil, fmt.Errorf("invalid publishing target %s: %w", t.Type, err)
 		}
@@ -317,7 +598,8 @@ func loadCredentialsAndConfig(ctx context.Context, creds Credentials, publishing
 		targets = append(targets, target)
 	}
 
-	ocmTarget, err := cloudprovider.NewOCMTarget(publishingConfig.OCM.Type)
+	var ocmTarget cloudprovider.OCMTarget
+	ocmTarget, err = cloudprovider.NewOCMTarget(publishingConfig.OCM.Type)
 	if err != nil {
 		return nil, nil, nil, nil, nil, fmt.Errorf("invalid OCM target %s: %w", publishingConfig.OCM.Type, err)
 	}
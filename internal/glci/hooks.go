@@ -0,0 +1,327 @@
+package glci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// HookEvent is a point of a publish or remove operation at which hooks are run.
+type HookEvent string
+
+const (
+	// HookEventPreRelease occurs before the publications of a release are processed. A failing hook vetoes the operation.
+	HookEventPreRelease HookEvent = "pre_release"
+	// HookEventPostPublication occurs after each successful publication or removal.
+	HookEventPostPublication HookEvent = "post_publication"
+	// HookEventPostFailure occurs after each failed publication or removal.
+	HookEventPostFailure HookEvent = "post_failure"
+	// HookEventPostOCM occurs after the component descriptor of a release has been published.
+	HookEventPostOCM HookEvent = "post_ocm"
+)
+
+// HookPayload is passed to hooks as JSON, on standard input to commands and as request body to webhooks.
+type HookPayload struct {
+	Event                  HookEvent `json:"event"`
+	Operation              string    `json:"operation"`
+	Version                string    `json:"version"`
+	Commit                 string    `json:"commit"`
+	Cnames                 []string  `json:"cnames,omitempty"`
+	Cname                  string    `json:"cname,omitempty"`
+	Target                 string    `json:"target,omitempty"`
+	PublishingOutput       any       `json:"publishing_output,omitempty"`
+	PublishedImageMetadata any       `json:"published_image_metadata,omitempty"`
+	Repository             string    `json:"repository,omitempty"`
+	Error                  string    `json:"error,omitempty"`
+}
+
+// cfgHook runs either a local command or a webhook at the given events.
+type cfgHook struct {
+	Events  []HookEvent       `mapstructure:"events"`
+	Command []string          `mapstructure:"command,omitempty"`
+	URL     string            `mapstructure:"url,omitempty"`
+	Headers map[string]string `mapstructure:"headers,omitempty"`
+	Timeout *time.Duration    `mapstructure:"timeout,omitempty"`
+}
+
+const defaultHookTimeout = time.Minute
+
+func (c *cfgHook) validate() error {
+	if len(c.Events) == 0 {
+		return errors.New("missing events")
+	}
+	for _, event := range c.Events {
+		switch event {
+		case HookEventPreRelease, HookEventPostPublication, HookEventPostFailure, HookEventPostOCM:
+		default:
+			return fmt.Errorf("unknown event %s", event)
+		}
+	}
+
+	switch {
+	case len(c.Command) > 0 && c.URL != "":
+		return errors.New("command and URL are mutually exclusive")
+	case len(c.Command) > 0:
+		if len(c.Headers) > 0 {
+			return errors.New("headers are only supported for webhooks")
+		}
+	case c.URL != "":
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return fmt.Errorf("invalid URL: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid URL scheme %s", u.Scheme)
+		}
+	default:
+		return errors.New("missing command or URL")
+	}
+
+	if c.Timeout != nil && *c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout: %s", *c.Timeout)
+	}
+
+	return nil
+}
+
+func (c *cfgHook) name() string {
+	if len(c.Command) > 0 {
+		return c.Command[0]
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return c.URL
+	}
+	return u.Redacted()
+}
+
+// releaseHooks runs the configured hooks for the events of an operation on a release.
+type releaseHooks struct {
+	hooks     []cfgHook
+	operation string
+	version   string
+	commit    string
+}
+
+func newReleaseHooks(hooks []cfgHook, operation, version, commit string) *releaseHooks {
+	return &releaseHooks{
+		hooks:     hooks,
+		operation: operation,
+		version:   version,
+		commit:    commit,
+	}
+}
+
+// preRelease runs the hooks before a release and returns an error if any of them has failed.
+func (h *releaseHooks) preRelease(ctx context.Context, publications []cloudprovider.Publication) error {
+	cnames := make([]string, 0, len(publications))
+	for _, publication := range publications {
+		if !slices.Contains(cnames, publication.Cname) {
+			cnames = append(cnames, publication.Cname)
+		}
+	}
+
+	err := h.run(ctx, HookPayload{
+		Event:  HookEventPreRelease,
+		Cnames: cnames,
+	})
+	if err != nil {
+		return fmt.Errorf("vetoed by hook: %w", err)
+	}
+
+	return nil
+}
+
+func (h *releaseHooks) postPublication(ctx context.Context, publication cloudprovider.Publication, output cloudprovider.PublishingOutput,
+	metadata *gl.PublishedImageMetadata,
+) {
+	h.runLogged(ctx, HookPayload{
+		Event:                  HookEventPostPublication,
+		Cname:                  publication.Cname,
+		Target:                 publication.Target.Type(),
+		PublishingOutput:       output,
+		PublishedImageMetadata: metadata,
+	})
+}
+
+func (h *releaseHooks) postOCM(ctx context.Context, ocmTarget cloudprovider.OCMTarget) {
+	h.runLogged(ctx, HookPayload{
+		Event:      HookEventPostOCM,
+		Repository: ocmTarget.OCMRepository(),
+	})
+}
+
+// failures runs the failure hooks whenever a publication fails.
+func (h *releaseHooks) failures(publish func(context.Context, cloudprovider.Publication) error,
+) func(context.Context, cloudprovider.Publication) error {
+	return func(ctx context.Context, publication cloudprovider.Publication) error {
+		err := publish(ctx, publication)
+		if err != nil {
+			h.runLogged(context.WithoutCancel(ctx), HookPayload{
+				Event:  HookEventPostFailure,
+				Cname:  publication.Cname,
+				Target: publication.Target.Type(),
+				Error:  err.Error(),
+			})
+		}
+
+		return err
+	}
+}
+
+// runLogged runs the hooks of an event after which nothing can be vetoed anymore, failures are only logged.
+func (h *releaseHooks) runLogged(ctx context.Context, payload HookPayload) {
+	err := h.run(ctx, payload)
+	if err != nil {
+		log.Error(ctx, err, "event", payload.Event)
+	}
+}
+
+func (h *releaseHooks) run(ctx context.Context, payload HookPayload) error {
+	payload.Operation = h.operation
+	payload.Version = h.version
+	payload.Commit = h.commit
+
+	var body []byte
+	var errs []error
+	for _, hook := range h.hooks {
+		if !slices.Contains(hook.Events, payload.Event) {
+			continue
+		}
+
+		if body == nil {
+			var err error
+			body, err = marshalHookPayload(payload)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := runHook(ctx, hook, payload.Event, body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hook %s failed at %s: %w", hook.name(), payload.Event, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// marshalHookPayload encodes a payload as JSON. Publishing outputs only carry YAML field names, so they are converted into their generic
+// YAML representation first.
+func marshalHookPayload(payload HookPayload) ([]byte, error) {
+	var err error
+	payload.PublishingOutput, err = toGeneric(payload.PublishingOutput)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publishing output: %w", err)
+	}
+	payload.PublishedImageMetadata, err = toGeneric(payload.PublishedImageMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid published image metadata: %w", err)
+	}
+
+	var body []byte
+	body, err = json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hook payload: %w", err)
+	}
+
+	return body, nil
+}
+
+func toGeneric(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	y, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Directly wraps the YAML encoder.
+	}
+	var generic any
+	err = yaml.Unmarshal(y, &generic)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Directly wraps the YAML decoder.
+	}
+
+	return generic, nil
+}
+
+func runHook(ctx context.Context, hook cfgHook, event HookEvent, body []byte) error {
+	timeout := defaultHookTimeout
+	if hook.Timeout != nil {
+		timeout = *hook.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ctx = log.WithValues(ctx, "hook", hook.name(), "event", event)
+
+	log.Debug(ctx, "Running hook")
+	if len(hook.Command) > 0 {
+		return runCommandHook(ctx, hook, event, body)
+	}
+	return runWebhook(ctx, hook, body)
+}
+
+func runCommandHook(ctx context.Context, hook cfgHook, event HookEvent, body []byte) error {
+	//nolint:gosec // The command is taken from the configuration.
+	c := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	c.Stdin = bytes.NewReader(body)
+	c.Env = append(os.Environ(), "GLCI_HOOK_EVENT="+string(event))
+	output, err := c.CombinedOutput()
+	if len(output) > 0 {
+		log.Debug(ctx, "Hook output", "output", string(output))
+	}
+	if err != nil {
+		msg := strings.TrimSpace(string(output))
+		if msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err //nolint:wrapcheck // Directly wraps the command.
+	}
+
+	return nil
+}
+
+func runWebhook(ctx context.Context, hook cfgHook, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+
+	var resp *http.Response
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot call webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package glci
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+const releaseIndexPrefix = "meta/releases/"
+
+func releaseIndexKey(version, commit string) string {
+	return fmt.Sprintf("%s%s-%.8s.yaml", releaseIndexPrefix, version, commit)
+}
+
+// RebuildReleaseIndexes rebuilds the release indexes from the manifests of the flavors. Without a version, the indexes of all releases
+// are rebuilt, without a commit those of all commits of the version. Each index is rebuilt while holding the lock of its release. It
+// returns the keys of the rebuilt indexes.
+func RebuildReleaseIndexes(ctx context.Context, publishingConfig PublishingConfig, creds Credentials, version, commit string,
+) ([]string, error) {
+	ctx = log.WithValues(ctx, "op", "rebuild-index")
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	_, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	log.Debug(ctx, "Listing manifests")
+	var objects []cloudprovider.ObjectInfo
+	objects, err = manifestTarget.ListObjects(ctx, manifestPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list manifests: %w", err)
+	}
+
+	var releases [][2]string
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, "/") || cloudprovider.IsManifestSignature(object.Key) {
+			continue
+		}
+
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetManifest(ctx, manifestTarget, object.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get manifest %s: %w", object.Key, err)
+		}
+		release := [2]string{manifest.Version, manifest.BuildCommittish}
+		if (version != "" && release[0] != version) || !strings.HasPrefix(release[1], commit) || slices.Contains(releases, release) {
+			continue
+		}
+		releases = append(releases, release)
+	}
+	if len(releases) == 0 {
+		return nil, errors.New("no manifests found")
+	}
+	slices.SortFunc(releases, func(a, b [2]string) int {
+		return cmp.Or(strings.Compare(a[0], b[0]), strings.Compare(a[1], b[1]))
+	})
+
+	keys := make([]string, 0, len(releases))
+	for _, release := range releases {
+		rctx := log.WithValues(ctx, "version", release[0], "commit", release[1])
+
+		var lock *releaseLock
+		rctx, lock, err = acquireLock(rctx, manifestTarget, release[0], release[1])
+		if err != nil {
+			return nil, fmt.Errorf("cannot lock release %s-%.8s: %w", release[0], release[1], err)
+		}
+
+		err = updateReleaseIndex(rctx, manifestTarget, release[0], release[1])
+		if err != nil {
+			_ = lock.release(rctx)
+			return nil, err
+		}
+
+		err = lock.release(rctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot release lock: %w", err)
+		}
+		keys = append(keys, releaseIndexKey(release[0], release[1]))
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return nil, fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return keys, nil
+}
+
+// updateReleaseIndex rewrites the index of a release from the manifests of its flavors in a single write, so that consumers never see a
+// partially updated index. The index is deleted if the release has no manifests left.
+func updateReleaseIndex(ctx context.Context, manifestTarget cloudprovider.ArtifactSource, version, commit string) error {
+	key := releaseIndexKey(version, commit)
+	ctx = log.WithValues(ctx, "index", key)
+
+	log.Debug(ctx, "Listing manifests")
+	objects, err := manifestTarget.ListObjects(ctx, manifestPrefix)
+	if err != nil {
+		return fmt.Errorf("cannot list manifests: %w", err)
+	}
+
+	suffix := fmt.Sprintf("-%s-%.8s", version, commit)
+	index := gl.ReleaseIndex{
+		Version:         version,
+		BuildCommittish: commit,
+	}
+	for _, object := range objects {
+		if !strings.HasSuffix(object.Key, suffix) {
+			continue
+		}
+
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetManifest(ctx, manifestTarget, object.Key)
+		if err != nil {
+			return fmt.Errorf("cannot get manifest %s: %w", object.Key, err)
+		}
+		cname := strings.TrimSuffix(path.Base(object.Key), suffix)
+		if manifest.Version != version || manifestKey(cname, manifest.Version, manifest.BuildCommittish) != object.Key {
+			log.Debug(ctx, "Ignoring manifest with unexpected key", "key", object.Key)
+			continue
+		}
+
+		if len(manifest.BuildCommittish) > len(index.BuildCommittish) {
+			index.BuildCommittish = manifest.BuildCommittish
+		}
+		index.Flavors = append(index.Flavors, gl.ReleaseIndexFlavor{
+			Cname:                  cname,
+			Architecture:           manifest.Architecture,
+			Platform:               manifest.Platform,
+			Modifiers:              manifest.Modifiers,
+			Paths:                  manifest.Paths,
+			PublishedImageMetadata: manifest.PublishedImageMetadata,
+		})
+	}
+
+	if len(index.Flavors) == 0 {
+		log.Info(ctx, "Deleting release index")
+		err = manifestTarget.DeleteObject(ctx, key)
+		if err != nil && !errors.As(err, &cloudprovider.KeyNotFoundError{}) {
+			return fmt.Errorf("cannot delete release index %s: %w", key, err)
+		}
+
+		return nil
+	}
+
+	slices.SortFunc(index.Flavors, func(a, b gl.ReleaseIndexFlavor) int {
+		return strings.Compare(a.Cname, b.Cname)
+	})
+	glciVer := glciVersion(ctx)
+	if glciVer != "" {
+		index.GLCIVersion = &glciVer
+	}
+
+	var buf bytes.Buffer
+	err = yaml.NewEncoder(&buf).Encode(index)
+	if err != nil {
+		return fmt.Errorf("invalid release index: %w", err)
+	}
+
+	log.Info(ctx, "Updating release index", "flavors", len(index.Flavors))
+	err = manifestTarget.PutObject(ctx, key, &buf)
+	if err != nil {
+		return fmt.Errorf("cannot put release index %s: %w", key, err)
+	}
+
+	return nil
+}
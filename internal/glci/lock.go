@@ -0,0 +1,412 @@
+package glci
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// Lease describes which run of GLCI holds the lock of a release and until when. A lease which is not renewed before it expires can be
+// reclaimed by any other run.
+type Lease struct {
+	Version  string    `json:"version"  yaml:"version"`
+	Commit   string    `json:"commit"   yaml:"commit"`
+	Owner    string    `json:"owner"    yaml:"owner"`
+	Hostname string    `json:"hostname" yaml:"hostname"`
+	PID      int       `json:"pid"      yaml:"pid"`
+	Started  time.Time `json:"started"  yaml:"started"`
+	Expires  time.Time `json:"expires"  yaml:"expires"`
+	Token    string    `json:"-"        yaml:"token"`
+}
+
+// LockedError indicates that a release is locked by another run of GLCI.
+type LockedError struct {
+	Lease Lease
+}
+
+func (e LockedError) Error() string {
+	return fmt.Sprintf("release %s-%.8s is locked by %s on %s (pid %d) since %s until %s, use glci unlock --force if that run has died",
+		e.Lease.Version, e.Lease.Commit, e.Lease.Owner, e.Lease.Hostname, e.Lease.PID, e.Lease.Started.Format(time.RFC3339),
+		e.Lease.Expires.Format(time.RFC3339))
+}
+
+var errLockLost = errors.New("release lock has been lost")
+
+const (
+	lockPrefix = "meta/locks/"
+	// lockTTL is how long a lease is valid without being renewed. Leases are renewed three times per TTL.
+	lockTTL = 5 * time.Minute
+	// lockSettleTime is how long to wait before verifying that a lease has been acquired. Artifact sources offer no conditional writes,
+	// two runs acquiring the same lock at the same time are detected by the run whose lease has been overwritten.
+	lockSettleTime = 2 * time.Second
+)
+
+// Unlock forcefully releases the lock of a release, for example if the run holding it has died and the lease should not be left to
+// expire. If no commit is given, it is discovered from the existing locks of the version.
+func Unlock(ctx context.Context, publishingConfig PublishingConfig, creds Credentials, version, commit string) (Lease, error) {
+	ctx = log.WithValues(ctx, "op", "unlock", "version", version, "commit", commit)
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	_, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return Lease{}, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	var key string
+	key, err = findLock(ctx, manifestTarget, version, commit)
+	if err != nil {
+		return Lease{}, err
+	}
+
+	var lease *Lease
+	lease, err = getLease(ctx, manifestTarget, key)
+	if err != nil {
+		return Lease{}, err
+	}
+	if lease == nil {
+		return Lease{}, fmt.Errorf("release %s-%.8s is not locked", version, commit)
+	}
+
+	log.Info(ctx, "Releasing lock", "owner", lease.Owner, "hostname", lease.Hostname, "pid", lease.PID, "expires", lease.Expires)
+	err = manifestTarget.DeleteObject(ctx, key)
+	if err != nil {
+		return Lease{}, fmt.Errorf("cannot delete lock: %w", err)
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return Lease{}, fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return *lease, nil
+}
+
+type releaseLock struct {
+	source   cloudprovider.ArtifactSource
+	key      string
+	mutex    sync.Mutex
+	lease    Lease
+	stop     chan struct{}
+	done     chan struct{}
+	released bool
+}
+
+// acquireLock takes the lock of a release, reclaiming it if its lease has expired, and keeps renewing the lease until the lock is
+// released. The returned context is cancelled if the lease is lost to another run.
+func acquireLock(ctx context.Context, source cloudprovider.ArtifactSource, version, commit string) (context.Context, *releaseLock,
+	error,
+) {
+	key := lockKey(version, commit)
+	ctx = log.WithValues(ctx, "lock", key)
+
+	existing, err := getLease(ctx, source, key)
+	if err != nil {
+		return ctx, nil, err
+	}
+	now := time.Now()
+	if existing != nil {
+		if now.Before(existing.Expires) {
+			return ctx, nil, LockedError{
+				Lease: *existing,
+			}
+		}
+		log.Info(ctx, "Reclaiming expired lock", "owner", existing.Owner, "hostname", existing.Hostname, "pid", existing.PID,
+			"expired", existing.Expires)
+	}
+
+	var token string
+	token, err = newLockToken()
+	if err != nil {
+		return ctx, nil, err
+	}
+	l := &releaseLock{
+		source: source,
+		key:    key,
+		lease: Lease{
+			Version:  version,
+			Commit:   fmt.Sprintf("%.8s", commit),
+			Owner:    lockOwner(),
+			Hostname: lockHostname(),
+			PID:      os.Getpid(),
+			Started:  now.UTC().Truncate(time.Second),
+			Expires:  now.Add(lockTTL).UTC().Truncate(time.Second),
+			Token:    token,
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	log.Info(ctx, "Acquiring lock")
+	err = putLease(ctx, source, key, l.lease)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx, nil, fmt.Errorf("cannot acquire lock: %w", context.Cause(ctx))
+	case <-time.After(lockSettleTime):
+	}
+
+	var held bool
+	held, err = l.isHeld(ctx)
+	if err != nil {
+		return ctx, nil, err
+	}
+	if !held {
+		var current *Lease
+		current, err = getLease(ctx, source, key)
+		if err != nil {
+			return ctx, nil, err
+		}
+		if current != nil {
+			return ctx, nil, LockedError{
+				Lease: *current,
+			}
+		}
+		return ctx, nil, errLockLost
+	}
+
+	lctx, cancel := context.WithCancelCause(ctx)
+	go l.renew(lctx, cancel)
+
+	return lctx, l, nil
+}
+
+// release stops renewing the lease and deletes the lock unless it has been taken over. Releasing a lock more than once has no effect.
+func (l *releaseLock) release(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.released {
+		return nil
+	}
+	l.released = true
+
+	close(l.stop)
+	<-l.done
+
+	ctx = context.WithoutCancel(ctx)
+	held, err := l.isHeld(ctx)
+	if err != nil {
+		return err
+	}
+	if !held {
+		log.Info(ctx, "Lock has been taken over, not releasing it")
+		return nil
+	}
+
+	log.Debug(ctx, "Releasing lock")
+	err = l.source.DeleteObject(ctx, l.key)
+	if err != nil {
+		return fmt.Errorf("cannot delete lock: %w", err)
+	}
+
+	return nil
+}
+
+func (l *releaseLock) renew(ctx context.Context, cancel context.CancelCauseFunc) {
+	defer close(l.done)
+	defer cancel(nil)
+
+	ticker := time.NewTicker(lockTTL / 3)
+	defer ticker.Stop()
+
+	expires := l.lease.Expires
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if time.Now().After(expires) {
+			log.Error(ctx, errors.New("lease of release lock has expired"))
+			cancel(errLockLost)
+			return
+		}
+
+		held, err := l.isHeld(ctx)
+		if err != nil {
+			log.Error(ctx, fmt.Errorf("cannot renew lock: %w", err))
+			continue
+		}
+		if !held {
+			log.Error(ctx, errLockLost)
+			cancel(errLockLost)
+			return
+		}
+
+		lease := l.lease
+		lease.Expires = time.Now().Add(lockTTL).UTC().Truncate(time.Second)
+		err = putLease(ctx, l.source, l.key, lease)
+		if err != nil {
+			log.Error(ctx, fmt.Errorf("cannot renew lock: %w", err))
+			continue
+		}
+		expires = lease.Expires
+		log.Debug(ctx, "Lock renewed", "expires", expires)
+	}
+}
+
+func (l *releaseLock) isHeld(ctx context.Context) (bool, error) {
+	current, err := getLease(ctx, l.source, l.key)
+	if err != nil {
+		return false, err
+	}
+
+	return current != nil && current.Token == l.lease.Token, nil
+}
+
+func lockKey(version, commit string) string {
+	return fmt.Sprintf("%s%s-%.8s", lockPrefix, version, commit)
+}
+
+// findLock returns the key of the lock of a release. If no commit is given, the version must have exactly one lock.
+func findLock(ctx context.Context, source cloudprovider.ArtifactSource, version, commit string) (string, error) {
+	if commit != "" {
+		return lockKey(version, commit), nil
+	}
+
+	prefix := fmt.Sprintf("%s%s-", lockPrefix, version)
+	objects, err := source.ListObjects(ctx, prefix)
+	if err != nil {
+		return "", fmt.Errorf("cannot list locks: %w", err)
+	}
+
+	var commits []string
+	for _, object := range objects {
+		c := strings.TrimPrefix(object.Key, prefix)
+		if len(c) == 8 && !strings.Contains(c, "/") {
+			commits = append(commits, c)
+		}
+	}
+
+	switch len(commits) {
+	case 0:
+		return "", fmt.Errorf("version %s is not locked", version)
+	case 1:
+		return lockKey(version, commits[0]), nil
+	default:
+		slices.Sort(commits)
+		return "", fmt.Errorf("version %s is locked for multiple commits (%s), a commit must be given", version,
+			strings.Join(commits, ", "))
+	}
+}
+
+// activeLeases returns all leases which have not expired yet.
+func activeLeases(ctx context.Context, source cloudprovider.ArtifactSource) ([]Lease, error) {
+	objects, err := source.ListObjects(ctx, lockPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list locks: %w", err)
+	}
+
+	var leases []Lease
+	now := time.Now()
+	for _, object := range objects {
+		var lease *Lease
+		lease, err = getLease(ctx, source, object.Key)
+		if err != nil {
+			return nil, err
+		}
+		if lease != nil && now.Before(lease.Expires) {
+			leases = append(leases, *lease)
+		}
+	}
+
+	return leases, nil
+}
+
+func getLease(ctx context.Context, source cloudprovider.ArtifactSource, key string) (*Lease, error) {
+	body, err := source.GetObject(ctx, key)
+	if err != nil {
+		if errors.As(err, &cloudprovider.KeyNotFoundError{}) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot get lock %s: %w", key, err)
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var lease Lease
+	err = yaml.NewDecoder(body).Decode(&lease)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid lock %s: %w", key, err)
+	}
+
+	err = body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cannot close object: %w", err)
+	}
+
+	return &lease, nil
+}
+
+func putLease(ctx context.Context, source cloudprovider.ArtifactSource, key string, lease Lease) error {
+	var buf bytes.Buffer
+	err := yaml.NewEncoder(&buf).Encode(lease)
+	if err != nil {
+		return fmt.Errorf("invalid lock: %w", err)
+	}
+
+	err = source.PutObject(ctx, key, &buf)
+	if err != nil {
+		return fmt.Errorf("cannot put lock %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate lock token: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func lockOwner() string {
+	u, err := user.Current()
+	if err == nil && u.Username != "" {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
+
+func lockHostname() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+
+	return hostname
+}
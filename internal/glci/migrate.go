@@ -0,0 +1,200 @@
+package glci
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// MigratedManifest describes the migrations applied, or to be applied in a dry run, to a manifest.
+type MigratedManifest struct {
+	Key        string   `json:"key"              yaml:"key"`
+	Version    string   `json:"version"          yaml:"version"`
+	Commit     string   `json:"commit"           yaml:"commit"`
+	From       int      `json:"from"             yaml:"from"`
+	To         int      `json:"to"               yaml:"to"`
+	Migrations []string `json:"migrations"       yaml:"migrations"`
+	Backup     string   `json:"backup,omitempty" yaml:"backup,omitempty"`
+	Skipped    string   `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+const manifestBackupPrefix = "meta/backups/"
+
+// MigrateManifests upgrades all manifests written by older versions of GLCI or by the build system to the current manifest schema. The
+// manifests of a release are only rewritten while holding its lock. With backup, the original manifests are copied beneath
+// meta/backups/ first. A dry run only determines which migrations would be applied. Manifests which cannot be read because signatures
+// are required but they have been written before manifests were signed are skipped and reported along with the migrated ones.
+func MigrateManifests(ctx context.Context, publishingConfig PublishingConfig, creds Credentials, dryRun, backup bool,
+) ([]MigratedManifest, error) {
+	ctx = log.WithValues(ctx, "op", "migrate-manifests")
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	_, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	var migrated, skipped []MigratedManifest
+	migrated, skipped, err = planMigrations(ctx, manifestTarget)
+	if err != nil {
+		return nil, err
+	}
+	log.Info(ctx, "Manifests to migrate", "count", len(migrated), "skipped", len(skipped))
+
+	if !dryRun {
+		var backupPrefix string
+		if backup {
+			backupPrefix = manifestBackupPrefix + time.Now().UTC().Format("20060102T150405Z") + "/"
+		}
+
+		for start := 0; start < len(migrated); {
+			end := start + 1
+			for end < len(migrated) && migrated[end].Version == migrated[start].Version && migrated[end].Commit == migrated[start].Commit {
+				end++
+			}
+			err = migrateRelease(ctx, manifestTarget, migrated[start:end], backupPrefix)
+			if err != nil {
+				return nil, err
+			}
+			start = end
+		}
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return nil, fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return append(migrated, skipped...), nil
+}
+
+// planMigrations finds all manifests which are not at the current schema version, ordered by release so that the manifests of a release
+// can be migrated under a single lock. Unsigned manifests are returned separately if signatures are required.
+func planMigrations(ctx context.Context, manifestTarget cloudprovider.ArtifactSource) ([]MigratedManifest, []MigratedManifest, error) {
+	log.Debug(ctx, "Listing manifests")
+	objects, err := manifestTarget.ListObjects(ctx, manifestPrefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot list manifests: %w", err)
+	}
+
+	var migrated, skipped []MigratedManifest
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, "/") || cloudprovider.IsManifestSignature(object.Key) {
+			continue
+		}
+
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetStoredManifest(ctx, manifestTarget, object.Key)
+		if errors.Is(err, cloudprovider.ErrManifestNotSigned) {
+			log.Info(ctx, "Skipping unsigned manifest, it can be migrated without require_signed_manifests", "key", object.Key)
+			skipped = append(skipped, MigratedManifest{
+				Key:     object.Key,
+				Skipped: "not signed",
+			})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot get manifest %s: %w", object.Key, err)
+		}
+		from := manifest.SchemaVersion
+
+		var applied []gl.ManifestMigration
+		applied, err = manifest.Migrate()
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot migrate manifest %s: %w", object.Key, err)
+		}
+		if len(applied) == 0 {
+			continue
+		}
+
+		m := MigratedManifest{
+			Key:     object.Key,
+			Version: manifest.Version,
+			Commit:  manifest.BuildCommittish,
+			From:    from,
+			To:      manifest.SchemaVersion,
+		}
+		for _, migration := range applied {
+			m.Migrations = append(m.Migrations, migration.Description)
+		}
+		migrated = append(migrated, m)
+	}
+
+	slices.SortFunc(migrated, func(a, b MigratedManifest) int {
+		return cmp.Or(strings.Compare(a.Version, b.Version), strings.Compare(a.Commit, b.Commit), strings.Compare(a.Key, b.Key))
+	})
+
+	return migrated, skipped, nil
+}
+
+// migrateRelease migrates the manifests of a release while holding its lock. Manifests are read again after the lock has been acquired,
+// since a concurrent run might have changed them in the meantime.
+func migrateRelease(ctx context.Context, manifestTarget cloudprovider.ArtifactSource, migrated []MigratedManifest, backupPrefix string,
+) error {
+	version, commit := migrated[0].Version, migrated[0].Commit
+	ctx = log.WithValues(ctx, "version", version, "commit", commit)
+
+	ctx, lock, err := acquireLock(ctx, manifestTarget, version, commit)
+	if err != nil {
+		return fmt.Errorf("cannot lock release: %w", err)
+	}
+	defer func() {
+		_ = lock.release(ctx)
+	}()
+
+	for i := range migrated {
+		lctx := log.WithValues(ctx, "key", migrated[i].Key)
+
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetStoredManifest(lctx, manifestTarget, migrated[i].Key)
+		if err != nil {
+			return fmt.Errorf("cannot get manifest %s: %w", migrated[i].Key, err)
+		}
+
+		var applied []gl.ManifestMigration
+		applied, err = manifest.Migrate()
+		if err != nil {
+			return fmt.Errorf("cannot migrate manifest %s: %w", migrated[i].Key, err)
+		}
+		if len(applied) == 0 {
+			log.Info(lctx, "Manifest has already been migrated")
+			continue
+		}
+
+		if backupPrefix != "" {
+			backupKey := backupPrefix + strings.TrimPrefix(migrated[i].Key, manifestPrefix)
+			log.Info(lctx, "Backing up manifest", "backup", backupKey)
+			err = cloudprovider.CopyManifest(lctx, manifestTarget, migrated[i].Key, backupKey)
+			if err != nil {
+				return fmt.Errorf("cannot back up manifest %s: %w", migrated[i].Key, err)
+			}
+			migrated[i].Backup = backupKey
+		}
+
+		log.Info(lctx, "Migrating manifest", "from", migrated[i].From, "to", manifest.SchemaVersion)
+		err = cloudprovider.PutManifest(lctx, manifestTarget, migrated[i].Key, manifest)
+		if err != nil {
+			return fmt.Errorf("cannot put manifest %s: %w", migrated[i].Key, err)
+		}
+	}
+
+	err = lock.release(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot release lock: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package glci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// errNotStarted is the result of a publication that has not been started because of an earlier failure or cancellation.
+var errNotStarted = errors.New("not started")
+
+// runPublications runs a function for each publication, with at most parallelism publications in progress overall and at most
+// limits[type] publications in progress for a given target type. Publications are started in order for each target type, and each target
+// type is dispatched on its own so that a type which has reached its limit does not hold back publications of other types. Once a run
+// fails, no further publications are started unless keepGoing is set. It returns the result of each publication along with all failures
+// joined together.
+func runPublications(ctx context.Context, publications []cloudprovider.Publication, parallelism int, limits map[string]int,
+	keepGoing bool, run func(context.Context, cloudprovider.Publication) error,
+) ([]error, error) {
+	all := make(chan struct{}, max(parallelism, 1))
+	types := make(map[string]chan struct{}, len(limits))
+	for typ, limit := range limits {
+		types[typ] = make(chan struct{}, max(limit, 1))
+	}
+
+	results := make([]error, len(publications))
+	queues := make(map[string][]int)
+	var order []string
+	for i, publication := range publications {
+		results[i] = errNotStarted
+
+		typ := publication.Target.Type()
+		_, ok := queues[typ]
+		if !ok {
+			order = append(order, typ)
+		}
+		queues[typ] = append(queues[typ], i)
+	}
+
+	var wg sync.WaitGroup
+	var errsMutex sync.Mutex
+	var errs []error
+	fail := func(err error) {
+		errsMutex.Lock()
+		defer errsMutex.Unlock()
+		errs = append(errs, err)
+	}
+	failed := func() bool {
+		errsMutex.Lock()
+		defer errsMutex.Unlock()
+		return len(errs) != 0
+	}
+	var cancelOnce sync.Once
+	cancelled := func(err error) {
+		cancelOnce.Do(func() {
+			fail(err)
+		})
+	}
+
+	for _, t := range order {
+		typ := types[t]
+		queue := queues[t]
+
+		wg.Go(func() {
+			for _, i := range queue {
+				err := acquire(ctx, typ)
+				if err != nil {
+					cancelled(err)
+					return
+				}
+				err = acquire(ctx, all)
+				if err != nil {
+					release(typ)
+					cancelled(err)
+					return
+				}
+				if !keepGoing && failed() {
+					release(all)
+					release(typ)
+					return
+				}
+
+				wg.Go(func() {
+					defer release(typ)
+					defer release(all)
+
+					runErr := run(ctx, publications[i])
+					results[i] = runErr
+					if runErr != nil {
+						fail(runErr)
+					}
+				})
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// summarizeFailures logs a summary of the results of all publications and returns the failures grouped by cname and target.
+func summarizeFailures(ctx context.Context, op string, publications []cloudprovider.Publication, results []error) error {
+	var cnames []string
+	failures := make(map[string][]error)
+	var succeeded, failed, notStarted int
+	for i, publication := range publications {
+		switch {
+		case results[i] == nil:
+			succeeded++
+			continue
+		case errors.Is(results[i], errNotStarted):
+			notStarted++
+		default:
+			failed++
+		}
+
+		_, ok := failures[publication.Cname]
+		if !ok {
+			cnames = append(cnames, publication.Cname)
+		}
+		failures[publication.Cname] = append(failures[publication.Cname], fmt.Errorf("%s: %w", publication.Target.Type(), results[i]))
+	}
+
+	log.Info(ctx, op+" finished with failures", "succeeded", succeeded, "failed", failed, "notStarted", notStarted)
+
+	errs := make([]error, 0, len(cnames))
+	for _, cname := range cnames {
+		errs = append(errs, fmt.Errorf("%s: %w", cname, errors.Join(failures[cname]...)))
+	}
+
+	return errors.Join(errs...)
+}
+
+func acquire(ctx context.Context, sem chan struct{}) error {
+	if sem == nil {
+		return nil
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("cannot start publication: %w", ctx.Err())
+	}
+}
+
+func release(sem chan struct{}) {
+	if sem == nil {
+		return
+	}
+
+	<-sem
+}
+
+// manifestLocks serializes updates to the manifest shared by all publications of a cname.
+type manifestLocks struct {
+	mutex     sync.Mutex
+	locks     map[string]*sync.Mutex
+	manifests map[string]*gl.Manifest
+}
+
+// newManifestLocks takes over the manifests shared by the publications of each cname. It returns the publications with their own copies
+// of the manifests, so that publishing targets never read a manifest while another publication of the same cname updates it.
+func newManifestLocks(publications []cloudprovider.Publication) (*manifestLocks, []cloudprovider.Publication) {
+	l := &manifestLocks{
+		locks:     make(map[string]*sync.Mutex),
+		manifests: make(map[string]*gl.Manifest),
+	}
+
+	own := make([]cloudprovider.Publication, len(publications))
+	for i, publication := range publications {
+		_, ok := l.manifests[publication.Cname]
+		if !ok {
+			l.manifests[publication.Cname] = publication.Manifest
+		}
+		own[i] = publication
+		own[i].Manifest = publication.Manifest.Clone()
+	}
+
+	return l, own
+}
+
+// lock locks the shared manifest of a cname and returns it along with a function to unlock it.
+func (l *manifestLocks) lock(cname string) (*gl.Manifest, func()) {
+	l.mutex.Lock()
+	lock, ok := l.locks[cname]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[cname] = lock
+	}
+	manifest := l.manifests[cname]
+	l.mutex.Unlock()
+
+	lock.Lock()
+	return manifest, lock.Unlock
+}
@@ -0,0 +1,442 @@
+package glci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// PlannedPublication describes what a publish or remove operation would do with a flavor on a publishing target.
+type PlannedPublication struct {
+	Cname    string     `json:"cname"    yaml:"cname"`
+	Platform string     `json:"platform" yaml:"platform"`
+	Target   string     `json:"target"   yaml:"target"`
+	Reason   PlanReason `json:"reason"   yaml:"reason"`
+}
+
+// PlanReason is the reason why a flavor is or is not acted upon on a publishing target.
+type PlanReason string
+
+const (
+	// PlanReasonPublish means that the flavor would be published to the target.
+	PlanReasonPublish PlanReason = "to publish"
+	// PlanReasonResume means that an interrupted publication of the flavor to the target would be resumed.
+	PlanReasonResume PlanReason = "to resume"
+	// PlanReasonRemove means that the flavor would be removed from the target.
+	PlanReasonRemove PlanReason = "to remove"
+	// PlanReasonPublished means that the flavor is already published to the target.
+	PlanReasonPublished PlanReason = "already published"
+	// PlanReasonNotPublished means that the flavor is not published to the target.
+	PlanReasonNotPublished PlanReason = "not published"
+	// PlanReasonManifestMissing means that the manifest of the flavor could not be found.
+	PlanReasonManifestMissing PlanReason = "manifest missing"
+	// PlanReasonNoTarget means that no publishing target is configured for the platform of the flavor.
+	PlanReasonNoTarget PlanReason = "no target"
+)
+
+// PlanPublish determines what Publish would do without publishing anything.
+func PlanPublish(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig, creds Credentials, version,
+	commit string, selection Selection,
+) ([]PlannedPublication, error) {
+	ctx = log.WithValues(ctx, "op", "plan-publish", "version", version, "commit", commit)
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	manifestSource, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	var selectedFlavorsConfig FlavorsConfig
+	var selectedTargets []cloudprovider.PublishingTarget
+	selectedFlavorsConfig, selectedTargets, err = applySelection(ctx, selection, flavorsConfig, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []plannedPublication
+	plan, _, err = planPublish(ctx, selectedFlavorsConfig, manifestSource, manifestTarget, selectedTargets, version, commit, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return nil, fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return exportPlan(plan), nil
+}
+
+// PlanRemove determines what Remove would do without removing anything.
+func PlanRemove(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig, creds Credentials, version,
+	commit string, selection Selection,
+) ([]PlannedPublication, error) {
+	ctx = log.WithValues(ctx, "op", "plan-remove", "version", version, "commit", commit)
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	_, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	var selectedFlavorsConfig FlavorsConfig
+	var selectedTargets []cloudprovider.PublishingTarget
+	selectedFlavorsConfig, selectedTargets, err = applySelection(ctx, selection, flavorsConfig, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []plannedPublication
+	plan, _, err = planRemove(ctx, selectedFlavorsConfig, manifestTarget, selectedTargets, version, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return nil, fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return exportPlan(plan), nil
+}
+
+type plannedPublication struct {
+	publication cloudprovider.Publication
+	platform    string
+	reason      PlanReason
+	err         error
+}
+
+const manifestPrefix = "meta/singles/"
+
+func manifestKey(cname, version, commit string) string {
+	return fmt.Sprintf("%s%s-%s-%.8s", manifestPrefix, cname, version, commit)
+}
+
+// resolveCommit discovers the commit of a release from the manifests of the configured flavors if no commit has been given. It fails if
+// the version has been built from more than one commit.
+func resolveCommit(ctx context.Context, source cloudprovider.ArtifactSource, flavorsConfig FlavorsConfig, version, commit string,
+) (string, error) {
+	if commit != "" {
+		return commit, nil
+	}
+
+	log.Info(ctx, "Discovering commit")
+	var commits []string
+	for _, flavor := range flavorsConfig.Flavors {
+		prefix := fmt.Sprintf("%s%s-%s-", manifestPrefix, flavor.Cname, version)
+		objects, err := source.ListObjects(ctx, prefix)
+		if err != nil {
+			return "", fmt.Errorf("cannot list manifests for %s: %w", flavor.Cname, err)
+		}
+
+		for _, object := range objects {
+			c := strings.TrimPrefix(object.Key, prefix)
+			if len(c) != 8 || strings.Contains(c, "/") || slices.Contains(commits, c) {
+				continue
+			}
+			commits = append(commits, c)
+		}
+	}
+
+	switch len(commits) {
+	case 0:
+		return "", fmt.Errorf("no manifests found for version %s", version)
+	case 1:
+		log.Info(ctx, "Commit discovered", "commit", commits[0])
+		return commits[0], nil
+	default:
+		slices.Sort(commits)
+		return "", fmt.Errorf("version %s has been built from multiple commits (%s), a commit must be given", version,
+			strings.Join(commits, ", "))
+	}
+}
+
+func journalKey(cname, version, commit, typ string) string {
+	return fmt.Sprintf("meta/journals/%s-%s-%.8s-%s", cname, version, commit, strings.ToLower(typ))
+}
+
+func checkManifest(manifest *gl.Manifest, cname, version, commit string) error {
+	if manifest.Version != version {
+		return fmt.Errorf("manifest for %s has incorrect version %s", cname, manifest.Version)
+	}
+	if manifest.BuildCommittish != commit && fmt.Sprintf("%.8s", manifest.BuildCommittish) != commit {
+		return fmt.Errorf("manifest for %s has incorrect commit %s", cname, manifest.BuildCommittish)
+	}
+
+	return nil
+}
+
+// checkManifestCname ensures that the metadata of a manifest to be published agrees with the cname it has been stored under.
+func checkManifestCname(manifest *gl.Manifest, cname string) error {
+	c, err := gl.ParseCname(cname)
+	if err != nil {
+		return err //nolint:wrapcheck // Directly wraps the parser.
+	}
+	err = c.CheckManifest(manifest)
+	if err != nil {
+		return fmt.Errorf("manifest for %s contradicts its cname: %w", cname, err)
+	}
+
+	return nil
+}
+
+func planPublish(ctx context.Context, flavorsConfig FlavorsConfig, manifestSource, manifestTarget cloudprovider.ArtifactSource,
+	targets []cloudprovider.PublishingTarget, version, commit string, selection Selection,
+) ([]plannedPublication, string, error) {
+	var err error
+	commit, err = resolveCommit(ctx, manifestSource, flavorsConfig, version, commit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plan := make([]plannedPublication, 0, len(flavorsConfig.Flavors)*2)
+	for _, flavor := range flavorsConfig.Flavors {
+		flavorTargets := targetsForPlatform(targets, flavor.Platform)
+		if len(flavorTargets) == 0 {
+			plan = append(plan, noTargetPlan(flavor))
+			continue
+		}
+		key := manifestKey(flavor.Cname, version, commit)
+		lctx := log.WithValues(ctx, "cname", flavor.Cname, "platform", flavor.Platform)
+
+		log.Info(lctx, "Retrieving manifest")
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetManifest(lctx, manifestSource, key)
+		if err != nil {
+			if errors.As(err, &cloudprovider.KeyNotFoundError{}) {
+				log.Debug(lctx, "Manifest not found")
+				plan = append(plan, manifestMissingPlan(flavor, flavorTargets, err)...)
+				continue
+			}
+			return nil, "", fmt.Errorf("cannot get manifest for %s: %w", flavor.Cname, err)
+		}
+		err = checkManifest(manifest, flavor.Cname, version, commit)
+		if err != nil {
+			return nil, "", err
+		}
+		err = checkManifestCname(manifest, flavor.Cname)
+		if err != nil {
+			return nil, "", err
+		}
+		commit = manifest.BuildCommittish
+
+		log.Debug(lctx, "Retrieving target manifest")
+		var targetManifest *gl.Manifest
+		targetManifest, err = cloudprovider.GetManifest(lctx, manifestTarget, key)
+		if err != nil && !errors.As(err, &cloudprovider.KeyNotFoundError{}) {
+			return nil, "", fmt.Errorf("cannot get target manifest for %s: %w", flavor.Cname, err)
+		}
+		if targetManifest != nil {
+			if targetManifest.Version != version {
+				return nil, "", fmt.Errorf("target manifest for %s has incorrect version %s", flavor.Cname, targetManifest.Version)
+			}
+			if targetManifest.BuildCommittish != commit {
+				return nil, "", fmt.Errorf("target manifest for %s has incorrect commit %s", flavor.Cname, targetManifest.BuildCommittish)
+			}
+
+			// Publications are added to what has already been published to the manifest target.
+			manifest.PublishedImageMetadata = targetManifest.PublishedImageMetadata
+			manifest.PublishedAt = targetManifest.PublishedAt
+			manifest.IncompletePublications = targetManifest.IncompletePublications
+		}
+
+		for _, target := range flavorTargets {
+			reason := PlanReasonPublish
+			if targetManifest != nil {
+				var isPublished bool
+				isPublished, err = target.IsPublished(targetManifest)
+				if err != nil {
+					return nil, "", fmt.Errorf("cannot determine publishing status for %s: %w", flavor.Cname, err)
+				}
+				switch {
+				case isPublished && len(selection.Regions) == 0 && isIncomplete(targetManifest, target):
+					log.Info(lctx, "Published to some regions only, completing", "target", target.Type())
+				case isPublished:
+					log.Info(lctx, "Already published, skipping", "target", target.Type())
+					reason = PlanReasonPublished
+				}
+			}
+			if reason == PlanReasonPublish {
+				var journal *cloudprovider.Journal
+				journal, err = cloudprovider.LoadJournal(lctx, manifestTarget, journalKey(flavor.Cname, version, commit, target.Type()))
+				if err != nil {
+					return nil, "", fmt.Errorf("cannot load journal for %s: %w", flavor.Cname, err)
+				}
+				if journal.Len() > 0 {
+					log.Info(lctx, "Interrupted publication found", "target", target.Type(), "steps", journal.Len())
+					reason = PlanReasonResume
+				}
+			}
+
+			plan = append(plan, plannedPublication{
+				publication: cloudprovider.Publication{
+					Cname:    flavor.Cname,
+					Manifest: manifest,
+					Target:   target,
+				},
+				platform: flavor.Platform,
+				reason:   reason,
+			})
+		}
+	}
+
+	return plan, commit, nil
+}
+
+func planRemove(ctx context.Context, flavorsConfig FlavorsConfig, manifestTarget cloudprovider.ArtifactSource,
+	targets []cloudprovider.PublishingTarget, version, commit string,
+) ([]plannedPublication, string, error) {
+	var err error
+	commit, err = resolveCommit(ctx, manifestTarget, flavorsConfig, version, commit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plan := make([]plannedPublication, 0, len(flavorsConfig.Flavors)*2)
+	for _, flavor := range flavorsConfig.Flavors {
+		flavorTargets := targetsForPlatform(targets, flavor.Platform)
+		if len(flavorTargets) == 0 {
+			plan = append(plan, noTargetPlan(flavor))
+			continue
+		}
+		key := manifestKey(flavor.Cname, version, commit)
+		lctx := log.WithValues(ctx, "cname", flavor.Cname, "platform", flavor.Platform)
+
+		log.Info(lctx, "Retrieving manifest")
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetManifest(lctx, manifestTarget, key)
+		if err != nil {
+			if errors.As(err, &cloudprovider.KeyNotFoundError{}) {
+				log.Debug(lctx, "Manifest not found, skipping")
+				plan = append(plan, manifestMissingPlan(flavor, flavorTargets, err)...)
+				continue
+			}
+			return nil, "", fmt.Errorf("cannot get manifest for %s: %w", flavor.Cname, err)
+		}
+		err = checkManifest(manifest, flavor.Cname, version, commit)
+		if err != nil {
+			return nil, "", err
+		}
+		commit = manifest.BuildCommittish
+
+		for _, target := range flavorTargets {
+			// Any image within the selected regions is removed, even if not every selected region has been published to.
+			var images []cloudprovider.PublishedImage
+			images, err = target.PublishedImages(manifest)
+			if err != nil {
+				return nil, "", fmt.Errorf("cannot determine publishing status for %s: %w", flavor.Cname, err)
+			}
+			reason := PlanReasonRemove
+			if len(images) == 0 {
+				log.Debug(lctx, "Already removed, skipping", "target", target.Type())
+				reason = PlanReasonNotPublished
+			}
+
+			plan = append(plan, plannedPublication{
+				publication: cloudprovider.Publication{
+					Cname:    flavor.Cname,
+					Manifest: manifest,
+					Target:   target,
+				},
+				platform: flavor.Platform,
+				reason:   reason,
+			})
+		}
+	}
+
+	return plan, commit, nil
+}
+
+func targetsForPlatform(targets []cloudprovider.PublishingTarget, platform string) []cloudprovider.PublishingTarget {
+	var platformTargets []cloudprovider.PublishingTarget
+	for _, target := range targets {
+		if target.Type() == platform {
+			platformTargets = append(platformTargets, target)
+		}
+	}
+
+	return platformTargets
+}
+
+// targetNames returns a name for each publishing target which tells it apart from the other targets of the same type by the clouds,
+// projects or hypervisors it publishes to. Targets which cannot be told apart are numbered.
+func targetNames(targets []cloudprovider.PublishingTarget) []string {
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		name := target.Type()
+		clouds := target.Clouds()
+		if len(clouds) > 0 {
+			name += " (" + strings.Join(clouds, ", ") + ")"
+		}
+		unique := name
+		for n := 2; slices.Contains(names, unique); n++ {
+			unique = fmt.Sprintf("%s #%d", name, n)
+		}
+		names = append(names, unique)
+	}
+
+	return names
+}
+
+func noTargetPlan(flavor cfgFlavor) plannedPublication {
+	return plannedPublication{
+		publication: cloudprovider.Publication{
+			Cname: flavor.Cname,
+		},
+		platform: flavor.Platform,
+		reason:   PlanReasonNoTarget,
+	}
+}
+
+func manifestMissingPlan(flavor cfgFlavor, targets []cloudprovider.PublishingTarget, err error) []plannedPublication {
+	plan := make([]plannedPublication, 0, len(targets))
+	for _, target := range targets {
+		plan = append(plan, plannedPublication{
+			publication: cloudprovider.Publication{
+				Cname:  flavor.Cname,
+				Target: target,
+			},
+			platform: flavor.Platform,
+			reason:   PlanReasonManifestMissing,
+			err:      err,
+		})
+	}
+
+	return plan
+}
+
+func exportPlan(plan []plannedPublication) []PlannedPublication {
+	planned := make([]PlannedPublication, 0, len(plan))
+	for _, p := range plan {
+		var target string
+		if p.publication.Target != nil {
+			target = p.publication.Target.Type()
+		}
+
+		planned = append(planned, PlannedPublication{
+			Cname:    p.publication.Cname,
+			Platform: p.platform,
+			Target:   target,
+			Reason:   p.reason,
+		})
+	}
+
+	return planned
+}
@@ -0,0 +1,110 @@
+package glci
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// Promote publishes a release by reusing the images which have already been published with another publishing configuration, such as the
+// one of the integration test accounts, instead of importing them again. Publishing targets which cannot promote images import them as
+// Publish does. Manifests and the component descriptor are written as by Publish.
+func Promote(ctx context.Context, flavorsConfig FlavorsConfig, fromPublishingConfig PublishingConfig, fromCreds Credentials,
+	publishingConfig PublishingConfig, aliasesConfig AliasesConfig, creds Credentials, version, commit string, opts Options,
+) error {
+	fctx := log.WithValues(ctx, "op", "promote", "version", version, "commit", commit)
+	fctx = cloudprovider.WithRetryPolicy(fctx, fromPublishingConfig.retryPolicy())
+	fctx = cloudprovider.WithPollPolicy(fctx, fromPublishingConfig.pollPolicy())
+
+	log.Debug(fctx, "Loading source credentials and configuration")
+	_, fromManifestTarget, fromSources, fromTargets, fromOCMTarget, err := loadCredentialsAndConfig(fctx, fromCreds,
+		fromPublishingConfig)
+	if err != nil {
+		return fmt.Errorf("invalid source credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(fromSources, fromTargets, fromOCMTarget)
+	}()
+
+	err = publishRelease(ctx, "promote", flavorsConfig, publishingConfig, aliasesConfig, creds, version, commit, opts, &promotionSource{
+		manifestTarget: fromManifestTarget,
+		targets:        fromTargets,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = closeSourcesAndTargets(fromSources, fromTargets, fromOCMTarget)
+	if err != nil {
+		return fmt.Errorf("cannot close source sources and targets: %w", err)
+	}
+
+	return nil
+}
+
+// promotionSource is where the images of a promoted release have been published before.
+type promotionSource struct {
+	manifestTarget cloudprovider.ArtifactSource
+	targets        []cloudprovider.PublishingTarget
+}
+
+// promote publishes an image by promoting the image published by a source publishing target of the same type and cloud. If the
+// publishing target does not support promotion or no source publishing target has published the image in its cloud, the image is imported
+// instead.
+func (s *promotionSource) promote(ctx context.Context, publication cloudprovider.Publication, version, commit string,
+	sources map[string]cloudprovider.ArtifactSource,
+) (cloudprovider.PublishingOutput, error) {
+	promoter, ok := publication.Target.(cloudprovider.PromotingTarget)
+	if !ok {
+		log.Info(ctx, "Publishing target cannot promote images, importing image")
+		//nolint:wrapcheck // Directly wraps the publishing target.
+		return publication.Target.Publish(ctx, publication.Cname, publication.Manifest, sources)
+	}
+
+	key := manifestKey(publication.Cname, version, commit)
+	fromManifest, err := cloudprovider.GetManifest(ctx, s.manifestTarget, key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get source manifest %s: %w", key, err)
+	}
+
+	for _, from := range s.targets {
+		if from.Type() != publication.Target.Type() || !sharesCloud(from.Clouds(), publication.Target.Clouds()) {
+			continue
+		}
+
+		var images []cloudprovider.PublishedImage
+		images, err = from.PublishedImages(fromManifest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source manifest %s: %w", key, err)
+		}
+		if len(images) == 0 {
+			continue
+		}
+
+		log.Info(ctx, "Promoting image")
+		//nolint:wrapcheck // Directly wraps the publishing target.
+		return promoter.Promote(ctx, publication.Cname, publication.Manifest, from, fromManifest, sources)
+	}
+
+	log.Info(ctx, "Not published in the same cloud with the source configuration, importing image")
+	//nolint:wrapcheck // Directly wraps the publishing target.
+	return publication.Target.Publish(ctx, publication.Cname, publication.Manifest, sources)
+}
+
+// sharesCloud reports whether two publishing targets have a cloud in common. Publishing targets without clouds only share with each other.
+func sharesCloud(clouds, otherClouds []string) bool {
+	if len(clouds) == 0 || len(otherClouds) == 0 {
+		return len(clouds) == len(otherClouds)
+	}
+
+	for _, cld := range clouds {
+		if slices.Contains(otherClouds, cld) {
+			return true
+		}
+	}
+
+	return false
+}
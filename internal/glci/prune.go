@@ -0,0 +1,243 @@
+package glci
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// RetentionDecision is the outcome of applying the retention policy to a published release.
+type RetentionDecision struct {
+	Version   string    `json:"version"           yaml:"version"`
+	Commit    string    `json:"commit"            yaml:"commit"`
+	Published time.Time `json:"published"         yaml:"published"`
+	Flavors   []string  `json:"flavors,omitempty" yaml:"flavors,omitempty"`
+	Keep      bool      `json:"keep"              yaml:"keep"`
+	Reason    string    `json:"reason"            yaml:"reason"`
+	flavors   []cfgFlavor
+}
+
+// PlanPrune determines which published releases would be kept and which would be removed by the retention policy.
+func PlanPrune(ctx context.Context, publishingConfig PublishingConfig, creds Credentials) ([]RetentionDecision, error) {
+	ctx = log.WithValues(ctx, "op", "plan-prune")
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	_, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	var decisions []RetentionDecision
+	decisions, err = planPrune(ctx, publishingConfig.Retention, manifestTarget, targets, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return nil, fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return decisions, nil
+}
+
+// Prune removes all published releases which are not retained by the retention policy from all publishing targets. Each release is
+// removed like by Remove.
+func Prune(ctx context.Context, publishingConfig PublishingConfig, creds Credentials, opts Options) error {
+	decisions, err := PlanPrune(ctx, publishingConfig, creds)
+	if err != nil {
+		return err
+	}
+	ctx = log.WithValues(ctx, "op", "prune")
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	var errs []error
+	var pruned int
+	for _, decision := range decisions {
+		if decision.Keep {
+			continue
+		}
+		log.Info(ctx, "Pruning release", "version", decision.Version, "commit", decision.Commit, "reason", decision.Reason)
+
+		err = Remove(ctx, FlavorsConfig{
+			Flavors: decision.flavors,
+		}, publishingConfig, creds, decision.Version, decision.Commit, opts)
+		if err != nil {
+			err = fmt.Errorf("cannot prune release %s-%.8s: %w", decision.Version, decision.Commit, err)
+			if !opts.KeepGoing {
+				return err
+			}
+			log.Error(ctx, err)
+			errs = append(errs, err)
+			continue
+		}
+		pruned++
+	}
+
+	if len(errs) > 0 {
+		log.Info(ctx, "Pruning finished with failures", "pruned", pruned, "failed", len(errs))
+		return errors.Join(errs...)
+	}
+	log.Info(ctx, "Pruning completed successfully", "pruned", pruned)
+	return nil
+}
+
+func planPrune(ctx context.Context, policy *cfgRetention, manifestTarget cloudprovider.ArtifactSource,
+	targets []cloudprovider.PublishingTarget, now time.Time,
+) ([]RetentionDecision, error) {
+	if policy == nil {
+		return nil, errors.New("missing retention policy")
+	}
+
+	decisions, err := findPublishedReleases(ctx, manifestTarget, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the newest patch versions of each minor version are retained, all commits of a retained version are kept alike.
+	newest := make(map[string][]*semver.Version)
+	for _, decision := range decisions {
+		minor, ver, ok := splitVersion(decision.Version)
+		if !ok {
+			continue
+		}
+		if !slices.ContainsFunc(newest[minor], ver.Equal) {
+			newest[minor] = append(newest[minor], ver)
+		}
+	}
+	for minor, versions := range newest {
+		slices.SortFunc(versions, func(a, b *semver.Version) int {
+			return b.Compare(a)
+		})
+		if policy.KeepPatches != nil && len(versions) > *policy.KeepPatches {
+			newest[minor] = versions[:*policy.KeepPatches]
+		}
+	}
+
+	for i, decision := range decisions {
+		age := now.Sub(decision.Published)
+		minor, ver, ok := splitVersion(decision.Version)
+
+		switch {
+		case slices.Contains(policy.Pinned, decision.Version):
+			decisions[i].Keep, decisions[i].Reason = true, "pinned"
+		case policy.TTL != nil && age > *policy.TTL:
+			decisions[i].Reason = "TTL of " + policy.TTL.String() + " expired"
+		case policy.KeepNewerThan != nil && age <= *policy.KeepNewerThan:
+			decisions[i].Keep, decisions[i].Reason = true, "newer than "+policy.KeepNewerThan.String()
+		case policy.KeepPatches != nil && ok && slices.ContainsFunc(newest[minor], ver.Equal):
+			decisions[i].Keep, decisions[i].Reason = true, fmt.Sprintf("one of the newest %d patch versions", *policy.KeepPatches)
+		case policy.KeepPatches == nil && policy.KeepNewerThan == nil:
+			decisions[i].Keep, decisions[i].Reason = true, "within TTL"
+		case policy.KeepPatches != nil && !ok:
+			decisions[i].Keep, decisions[i].Reason = true, "not a patch version"
+		default:
+			decisions[i].Reason = "not retained"
+		}
+	}
+
+	return decisions, nil
+}
+
+// findPublishedReleases finds all releases which have at least one manifest that is published on one of the publishing targets. The
+// publication time of a release is the latest time that one of its manifests has first been published. Manifests which predate the
+// recording of publication times fall back to the time they have last been modified.
+func findPublishedReleases(ctx context.Context, manifestTarget cloudprovider.ArtifactSource, targets []cloudprovider.PublishingTarget,
+) ([]RetentionDecision, error) {
+	log.Debug(ctx, "Listing manifests")
+	objects, err := manifestTarget.ListObjects(ctx, manifestPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list manifests: %w", err)
+	}
+
+	releases := make(map[string]*RetentionDecision)
+	for _, object := range objects {
+		if strings.HasSuffix(object.Key, "/") || cloudprovider.IsManifestSignature(object.Key) {
+			continue
+		}
+
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetManifest(ctx, manifestTarget, object.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get manifest %s: %w", object.Key, err)
+		}
+		cname := strings.TrimSuffix(path.Base(object.Key), fmt.Sprintf("-%s-%.8s", manifest.Version, manifest.BuildCommittish))
+		if manifestKey(cname, manifest.Version, manifest.BuildCommittish) != object.Key {
+			log.Debug(ctx, "Ignoring manifest with unexpected key", "key", object.Key)
+			continue
+		}
+
+		var published bool
+		published, err = isPublishedAnywhere(manifest, targets)
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine publishing status of manifest %s: %w", object.Key, err)
+		}
+		if !published {
+			continue
+		}
+
+		id := manifest.Version + "-" + manifest.BuildCommittish
+		release, ok := releases[id]
+		if !ok {
+			release = &RetentionDecision{
+				Version: manifest.Version,
+				Commit:  manifest.BuildCommittish,
+			}
+			releases[id] = release
+		}
+		release.Flavors = append(release.Flavors, cname)
+		release.flavors = append(release.flavors, cfgFlavor{
+			Platform: manifest.Platform,
+			Cname:    cname,
+		})
+		publishedAt := object.LastModified
+		if manifest.PublishedAt != nil {
+			publishedAt = *manifest.PublishedAt
+		}
+		if publishedAt.After(release.Published) {
+			release.Published = publishedAt
+		}
+	}
+
+	decisions := make([]RetentionDecision, 0, len(releases))
+	for _, release := range releases {
+		decisions = append(decisions, *release)
+	}
+	slices.SortFunc(decisions, func(a, b RetentionDecision) int {
+		return cmp.Or(b.Published.Compare(a.Published), strings.Compare(a.Version, b.Version), strings.Compare(a.Commit, b.Commit))
+	})
+
+	return decisions, nil
+}
+
+// splitVersion splits a version into its minor version and the full version. The patch version is the last component of the version,
+// so that the minor version of Garden Linux 1592.4 is 1592.
+func splitVersion(version string) (string, *semver.Version, bool) {
+	idx := strings.LastIndex(version, ".")
+	if idx < 1 {
+		return "", nil, false
+	}
+	ver, err := semver.NewVersion(version)
+	if err != nil {
+		return "", nil, false
+	}
+
+	return version[:idx], ver, true
+}
@@ -0,0 +1,166 @@
+package glci
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+)
+
+// Report is the outcome of every publication considered by a publish or remove operation.
+type Report struct {
+	Operation string        `json:"operation"`
+	Version   string        `json:"version"`
+	Commit    string        `json:"commit,omitempty"`
+	Started   time.Time     `json:"started"`
+	Finished  time.Time     `json:"finished"`
+	Entries   []ReportEntry `json:"entries"`
+}
+
+// ReportOutcome is what has happened to a publication.
+type ReportOutcome string
+
+const (
+	// ReportOutcomePublished means that the image has been published.
+	ReportOutcomePublished ReportOutcome = "published"
+	// ReportOutcomeRemoved means that the image has been removed.
+	ReportOutcomeRemoved ReportOutcome = "removed"
+	// ReportOutcomeSkipped means that nothing had to be done or that the publication has not been started.
+	ReportOutcomeSkipped ReportOutcome = "skipped"
+	// ReportOutcomeFailed means that the publication has failed.
+	ReportOutcomeFailed ReportOutcome = "failed"
+)
+
+// ReportEntry is the outcome of a single publication. Images are the images published by the operation, or already published ones if
+// the publication has been skipped for that reason.
+type ReportEntry struct {
+	Cname    string                         `json:"cname"`
+	Target   string                         `json:"target"`
+	Outcome  ReportOutcome                  `json:"outcome"`
+	Reason   string                         `json:"reason,omitempty"`
+	Duration time.Duration                  `json:"duration_ns"`
+	Error    string                         `json:"error,omitempty"`
+	Images   []cloudprovider.PublishedImage `json:"images,omitempty"`
+}
+
+// reportBuilder collects the report of an operation while its publications are running.
+type reportBuilder struct {
+	mutex  sync.Mutex
+	report Report
+	runs   map[cloudprovider.Publication]publicationRun
+}
+
+type publicationRun struct {
+	duration time.Duration
+	images   []cloudprovider.PublishedImage
+}
+
+func newReportBuilder(operation, version string) *reportBuilder {
+	return &reportBuilder{
+		report: Report{
+			Operation: operation,
+			Version:   version,
+			Started:   time.Now().UTC(),
+		},
+		runs: make(map[cloudprovider.Publication]publicationRun),
+	}
+}
+
+func (b *reportBuilder) setCommit(commit string) {
+	b.report.Commit = commit
+}
+
+// skipped records a publication which has been skipped by the plan.
+func (b *reportBuilder) skipped(publication cloudprovider.Publication, reason PlanReason) {
+	entry := ReportEntry{
+		Cname:   publication.Cname,
+		Outcome: ReportOutcomeSkipped,
+		Reason:  string(reason),
+	}
+	if publication.Target != nil {
+		entry.Target = publication.Target.Type()
+		if reason == PlanReasonPublished && publication.Manifest != nil {
+			entry.Images, _ = publication.Target.PublishedImages(publication.Manifest)
+		}
+	}
+	b.report.Entries = append(b.report.Entries, entry)
+}
+
+// timed measures the duration of each publication.
+func (b *reportBuilder) timed(run func(context.Context, cloudprovider.Publication) error,
+) func(context.Context, cloudprovider.Publication) error {
+	return func(ctx context.Context, publication cloudprovider.Publication) error {
+		start := time.Now()
+		err := run(ctx, publication)
+
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		r := b.runs[publication]
+		r.duration = time.Since(start)
+		b.runs[publication] = r
+
+		return err
+	}
+}
+
+// output records the images found in the publishing output of a publication.
+func (b *reportBuilder) output(publication cloudprovider.Publication, output cloudprovider.PublishingOutput) {
+	images, _ := publication.Target.PublishedImages(&gl.Manifest{
+		PublishedImageMetadata: output,
+	})
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	r := b.runs[publication]
+	r.images = images
+	b.runs[publication] = r
+}
+
+// ran records the results of the publications which have been run, succeeded is the outcome of a successful publication.
+func (b *reportBuilder) ran(publications []cloudprovider.Publication, results []error, succeeded ReportOutcome) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for i, publication := range publications {
+		r := b.runs[publication]
+		entry := ReportEntry{
+			Cname:    publication.Cname,
+			Target:   publication.Target.Type(),
+			Outcome:  succeeded,
+			Duration: r.duration,
+			Images:   r.images,
+		}
+		switch {
+		case results[i] == nil:
+		case errors.Is(results[i], errNotStarted):
+			entry.Outcome = ReportOutcomeSkipped
+			entry.Reason = results[i].Error()
+		default:
+			entry.Outcome = ReportOutcomeFailed
+			entry.Error = results[i].Error()
+		}
+		b.report.Entries = append(b.report.Entries, entry)
+	}
+}
+
+// finish stores the report, ordered by cname and target, into dst if the caller has asked for one.
+func (b *reportBuilder) finish(dst *Report) {
+	if dst == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.report.Finished = time.Now().UTC()
+	slices.SortStableFunc(b.report.Entries, func(x, y ReportEntry) int {
+		return cmp.Or(strings.Compare(x.Cname, y.Cname), strings.Compare(x.Target, y.Target))
+	})
+	*dst = b.report
+}
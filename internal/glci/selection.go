@@ -0,0 +1,143 @@
+package glci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// Selection narrows a publish or remove operation to a subset of the configured flavors, platforms, publishing targets, clouds and
+// regions without changing the configuration. Flavors are glob patterns matched against the cname, everything else is matched
+// case-insensitively. An empty list does not narrow anything.
+type Selection struct {
+	Flavors   []string
+	Platforms []string
+	Targets   []string
+	Clouds    []string
+	Regions   []string
+}
+
+// IsEmpty returns whether the selection selects everything.
+func (s Selection) IsEmpty() bool {
+	return len(s.Flavors) == 0 && len(s.Platforms) == 0 && len(s.Targets) == 0 && len(s.Clouds) == 0 && len(s.Regions) == 0
+}
+
+// applySelection narrows the flavors and publishing targets to those selected. Unselected targets are left untouched, the selected ones
+// are narrowed to the selected clouds and regions. Flavors whose platform no longer has any selected target are excluded as well.
+func applySelection(ctx context.Context, selection Selection, flavorsConfig FlavorsConfig, targets []cloudprovider.PublishingTarget,
+) (FlavorsConfig, []cloudprovider.PublishingTarget, error) {
+	if selection.IsEmpty() {
+		return flavorsConfig, targets, nil
+	}
+
+	for _, pattern := range selection.Flavors {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return FlavorsConfig{}, nil, fmt.Errorf("invalid flavor pattern %s: %w", pattern, err)
+		}
+	}
+
+	selectedTargets := make([]cloudprovider.PublishingTarget, 0, len(targets))
+	for _, target := range targets {
+		if !matchesAny(selection.Targets, target.Type()) {
+			log.Info(ctx, "Excluding target", "target", target.Type())
+			continue
+		}
+
+		selected, err := target.SetFilter(ctx, cloudprovider.Filter{
+			Clouds:  selection.Clouds,
+			Regions: selection.Regions,
+		})
+		if err != nil {
+			return FlavorsConfig{}, nil, fmt.Errorf("cannot apply selection to %s: %w", target.Type(), err)
+		}
+		if !selected {
+			continue
+		}
+
+		selectedTargets = append(selectedTargets, target)
+	}
+
+	selectedFlavors := FlavorsConfig{
+		Flavors: make([]cfgFlavor, 0, len(flavorsConfig.Flavors)),
+	}
+	var excluded []string
+	for _, flavor := range flavorsConfig.Flavors {
+		if !matchesAny(selection.Platforms, flavor.Platform) || !matchesAnyPattern(selection.Flavors, flavor.Cname) {
+			excluded = append(excluded, flavor.Cname)
+			continue
+		}
+		if len(targetsForPlatform(targets, flavor.Platform)) > 0 && len(targetsForPlatform(selectedTargets, flavor.Platform)) == 0 {
+			excluded = append(excluded, flavor.Cname)
+			continue
+		}
+
+		selectedFlavors.Flavors = append(selectedFlavors.Flavors, flavor)
+	}
+	if len(excluded) > 0 {
+		log.Info(ctx, "Excluding flavors", "count", len(excluded), "cnames", excluded)
+	}
+	if len(selectedFlavors.Flavors) == 0 {
+		return FlavorsConfig{}, nil, errors.New("no flavors selected")
+	}
+	log.Info(ctx, "Selected flavors", "count", len(selectedFlavors.Flavors))
+
+	return selectedFlavors, selectedTargets, nil
+}
+
+func matchesAny(values []string, value string) bool {
+	return len(values) == 0 || slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
+
+func matchesAnyPattern(patterns []string, value string) bool {
+	return len(patterns) == 0 || slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, value)
+		return matched
+	})
+}
+
+// publicationKeys identifies the publications of a publishing target within a manifest, one per cloud it publishes to.
+func publicationKeys(target cloudprovider.PublishingTarget) []string {
+	clouds := target.Clouds()
+	if len(clouds) == 0 {
+		return []string{target.Type()}
+	}
+
+	keys := make([]string, 0, len(clouds))
+	for _, cld := range clouds {
+		keys = append(keys, target.Type()+"/"+cld)
+	}
+	return keys
+}
+
+// isIncomplete returns whether a manifest records a publication to a publishing target which has been restricted to some regions.
+func isIncomplete(manifest *gl.Manifest, target cloudprovider.PublishingTarget) bool {
+	return slices.ContainsFunc(publicationKeys(target), func(key string) bool {
+		return slices.Contains(manifest.IncompletePublications, key)
+	})
+}
+
+// recordCompleteness records in a manifest whether the publication to a publishing target covers all of its regions. A publication
+// restricted to some regions by a selection must not be mistaken for a complete one by later runs.
+func recordCompleteness(manifest *gl.Manifest, target cloudprovider.PublishingTarget, complete bool) {
+	keys := publicationKeys(target)
+	incomplete := slices.DeleteFunc(slices.Clone(manifest.IncompletePublications), func(key string) bool {
+		return slices.Contains(keys, key)
+	})
+	if !complete {
+		incomplete = append(incomplete, keys...)
+	}
+	if len(incomplete) == 0 {
+		incomplete = nil
+	}
+	manifest.IncompletePublications = incomplete
+}
@@ -0,0 +1,129 @@
+package glci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// ReleaseStatus is the publication state of a release on all publishing targets. Targets are named by their type and the clouds,
+// projects or hypervisors they publish to, so that several targets of the same type can be told apart.
+type ReleaseStatus struct {
+	Version string         `json:"version" yaml:"version"`
+	Commit  string         `json:"commit"  yaml:"commit"`
+	Targets []string       `json:"targets" yaml:"targets"`
+	Flavors []FlavorStatus `json:"flavors" yaml:"flavors"`
+}
+
+// FlavorStatus is the publication state of a flavor on the publishing targets for its platform.
+type FlavorStatus struct {
+	Cname    string         `json:"cname"             yaml:"cname"`
+	Platform string         `json:"platform"          yaml:"platform"`
+	Manifest bool           `json:"manifest"          yaml:"manifest"`
+	Targets  []TargetStatus `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
+
+// TargetStatus is the publication state of a flavor on a publishing target.
+type TargetStatus struct {
+	Target    string                         `json:"target"           yaml:"target"`
+	Type      string                         `json:"type"             yaml:"type"`
+	Published bool                           `json:"published"        yaml:"published"`
+	Images    []cloudprovider.PublishedImage `json:"images,omitempty" yaml:"images,omitempty"`
+}
+
+// Status determines the publication state of a release on all publishing targets without modifying anything.
+func Status(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig, creds Credentials, version,
+	commit string,
+) (ReleaseStatus, error) {
+	ctx = log.WithValues(ctx, "op", "status", "version", version, "commit", commit)
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	manifestSource, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return ReleaseStatus{}, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	commit, err = resolveCommit(ctx, manifestSource, flavorsConfig, version, commit)
+	if err != nil {
+		return ReleaseStatus{}, err
+	}
+
+	status := ReleaseStatus{
+		Version: version,
+		Commit:  commit,
+		Targets: targetNames(targets),
+		Flavors: make([]FlavorStatus, 0, len(flavorsConfig.Flavors)),
+	}
+
+	for _, flavor := range flavorsConfig.Flavors {
+		lctx := log.WithValues(ctx, "cname", flavor.Cname, "platform", flavor.Platform)
+		key := manifestKey(flavor.Cname, version, commit)
+		flavorStatus := FlavorStatus{
+			Cname:    flavor.Cname,
+			Platform: flavor.Platform,
+		}
+		for i, target := range targets {
+			if target.Type() == flavor.Platform {
+				flavorStatus.Targets = append(flavorStatus.Targets, TargetStatus{
+					Target: status.Targets[i],
+					Type:   target.Type(),
+				})
+			}
+		}
+
+		log.Debug(lctx, "Retrieving manifest")
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetManifest(lctx, manifestSource, key)
+		if err != nil {
+			if !errors.As(err, &cloudprovider.KeyNotFoundError{}) {
+				return ReleaseStatus{}, fmt.Errorf("cannot get manifest for %s: %w", flavor.Cname, err)
+			}
+			status.Flavors = append(status.Flavors, flavorStatus)
+			continue
+		}
+		err = checkManifest(manifest, flavor.Cname, version, commit)
+		if err != nil {
+			return ReleaseStatus{}, err
+		}
+		status.Commit = manifest.BuildCommittish
+		flavorStatus.Manifest = true
+
+		log.Debug(lctx, "Retrieving target manifest")
+		var targetManifest *gl.Manifest
+		targetManifest, err = cloudprovider.GetManifest(lctx, manifestTarget, key)
+		if err != nil && !errors.As(err, &cloudprovider.KeyNotFoundError{}) {
+			return ReleaseStatus{}, fmt.Errorf("cannot get target manifest for %s: %w", flavor.Cname, err)
+		}
+
+		if targetManifest != nil {
+			for i, target := range targetsForPlatform(targets, flavor.Platform) {
+				flavorStatus.Targets[i].Published, err = target.IsPublished(targetManifest)
+				if err != nil {
+					return ReleaseStatus{}, fmt.Errorf("cannot determine publishing status for %s: %w", flavor.Cname, err)
+				}
+				flavorStatus.Targets[i].Images, err = target.PublishedImages(targetManifest)
+				if err != nil {
+					return ReleaseStatus{}, fmt.Errorf("cannot get published images for %s: %w", flavor.Cname, err)
+				}
+			}
+		}
+
+		status.Flavors = append(status.Flavors, flavorStatus)
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return ReleaseStatus{}, fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,107 @@
+package glci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/gl"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// Drift is a discrepancy between the manifest of a flavor and what actually exists on a publishing target.
+type Drift struct {
+	Cname   string                `json:"cname"            yaml:"cname"`
+	Target  string                `json:"target"           yaml:"target"`
+	Cloud   string                `json:"cloud,omitempty"  yaml:"cloud,omitempty"`
+	Region  string                `json:"region,omitempty" yaml:"region,omitempty"`
+	ID      string                `json:"id,omitempty"     yaml:"id,omitempty"`
+	Problem cloudprovider.Problem `json:"problem"          yaml:"problem"`
+	Detail  string                `json:"detail,omitempty" yaml:"detail,omitempty"`
+}
+
+// Verify checks that the images recorded in the manifests of a release still exist on all publishing targets, are available and are
+// public.
+func Verify(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig, creds Credentials, version,
+	commit string,
+) ([]Drift, error) {
+	ctx = log.WithValues(ctx, "op", "verify", "version", version, "commit", commit)
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	_, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	commit, err = resolveCommit(ctx, manifestTarget, flavorsConfig, version, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []Drift
+	for _, flavor := range flavorsConfig.Flavors {
+		flavorTargets := targetsForPlatform(targets, flavor.Platform)
+		if len(flavorTargets) == 0 {
+			continue
+		}
+		lctx := log.WithValues(ctx, "cname", flavor.Cname, "platform", flavor.Platform)
+
+		log.Debug(lctx, "Retrieving manifest")
+		var manifest *gl.Manifest
+		manifest, err = cloudprovider.GetManifest(lctx, manifestTarget, manifestKey(flavor.Cname, version, commit))
+		if err != nil {
+			if errors.As(err, &cloudprovider.KeyNotFoundError{}) {
+				log.Debug(lctx, "Manifest not found, skipping")
+				continue
+			}
+			return nil, fmt.Errorf("cannot get manifest for %s: %w", flavor.Cname, err)
+		}
+		err = checkManifest(manifest, flavor.Cname, version, commit)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, target := range flavorTargets {
+			// Incomplete publications are verified as well, their missing regions are reported as problems.
+			var images []cloudprovider.PublishedImage
+			images, err = target.PublishedImages(manifest)
+			if err != nil {
+				return nil, fmt.Errorf("cannot determine publishing status for %s: %w", flavor.Cname, err)
+			}
+			if len(images) == 0 {
+				continue
+			}
+
+			log.Info(lctx, "Verifying images", "target", target.Type())
+			var problems []cloudprovider.ImageProblem
+			problems, err = target.Verify(lctx, manifest)
+			if err != nil {
+				return nil, fmt.Errorf("cannot verify %s on %s: %w", flavor.Cname, target.Type(), err)
+			}
+			for _, problem := range problems {
+				drift = append(drift, Drift{
+					Cname:   flavor.Cname,
+					Target:  target.Type(),
+					Cloud:   problem.Image.Cloud,
+					Region:  problem.Image.Region,
+					ID:      problem.Image.ID,
+					Problem: problem.Problem,
+					Detail:  problem.Detail,
+				})
+			}
+		}
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return nil, fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return drift, nil
+}
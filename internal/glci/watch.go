@@ -0,0 +1,387 @@
+package glci
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/goccy/go-yaml"
+
+	"github.com/gardenlinux/glci/internal/cloudprovider"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// WatchOptions control how new releases are detected and published by Watch.
+type WatchOptions struct {
+	// Interval is the delay between two polls of the manifest source.
+	Interval time.Duration
+	// Constraint is a semver constraint which versions must satisfy to be published, empty means all versions.
+	Constraint string
+	// MaxAttempts is the number of times the publication of a release is attempted before it is given up.
+	MaxAttempts int
+	// PublishExisting publishes releases which are already present when watching starts for the first time instead of skipping them.
+	PublishExisting bool
+	// Once polls the manifest source only once instead of until the context is done.
+	Once bool
+	// Publish controls how releases are published.
+	Publish Options
+}
+
+// WatchState is the way a release has been handled by Watch.
+type WatchState string
+
+const (
+	// WatchStatePublished means that the release has been published.
+	WatchStatePublished WatchState = "published"
+	// WatchStateFailed means that the publication of the release has failed.
+	WatchStateFailed WatchState = "failed"
+	// WatchStateSkipped means that the release was already present when watching started for the first time.
+	WatchStateSkipped WatchState = "skipped"
+)
+
+// WatchRecord is the persisted record of how a release has been handled by Watch.
+type WatchRecord struct {
+	Version  string     `json:"version"         yaml:"version"`
+	Commit   string     `json:"commit"          yaml:"commit"`
+	State    WatchState `json:"state"           yaml:"state"`
+	Attempts int        `json:"attempts"        yaml:"attempts"`
+	Handled  time.Time  `json:"handled"         yaml:"handled"`
+	Error    string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+const (
+	watchPrefix      = "meta/watch/"
+	watchBaselineKey = "meta/watch-baseline"
+)
+
+// watchBaseline is the persisted set of releases which were present when watching started for the first time.
+type watchBaseline struct {
+	Created  time.Time `yaml:"created"`
+	Releases []string  `yaml:"releases"`
+}
+
+type watchedRelease struct {
+	version  string
+	commit   string
+	modified time.Time
+}
+
+// Watch polls the manifest source for new releases and publishes every release as soon as the manifests of all configured flavors are
+// present. Handled releases are recorded next to the manifests, so that watching can be restarted without publishing a release twice.
+// Failed publications are retried on later polls until MaxAttempts is reached.
+func Watch(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig, aliasesConfig AliasesConfig,
+	creds Credentials, opts WatchOptions,
+) error {
+	ctx = log.WithValues(ctx, "op", "watch")
+	ctx = cloudprovider.WithRetryPolicy(ctx, publishingConfig.retryPolicy())
+	ctx = cloudprovider.WithPollPolicy(ctx, publishingConfig.pollPolicy())
+
+	var constraint *semver.Constraints
+	if opts.Constraint != "" {
+		var err error
+		constraint, err = semver.NewConstraint(opts.Constraint)
+		if err != nil {
+			return fmt.Errorf("invalid version constraint %s: %w", opts.Constraint, err)
+		}
+	}
+	if opts.MaxAttempts < 1 {
+		return fmt.Errorf("invalid maximum number of attempts: %d", opts.MaxAttempts)
+	}
+	if !opts.Once && opts.Interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", opts.Interval)
+	}
+
+	log.Debug(ctx, "Loading credentials and configuration")
+	manifestSource, manifestTarget, sources, targets, ocmTarget, err := loadCredentialsAndConfig(ctx, creds, publishingConfig)
+	if err != nil {
+		return fmt.Errorf("invalid credentials or configuration: %w", err)
+	}
+	defer func() {
+		_ = closeSourcesAndTargets(sources, targets, ocmTarget)
+	}()
+
+	log.Info(ctx, "Watching for new releases", "interval", opts.Interval, "constraint", opts.Constraint)
+	var baseline map[string]struct{}
+	if opts.PublishExisting {
+		baseline = make(map[string]struct{})
+	}
+	for {
+		err = nil
+		if baseline == nil {
+			baseline, err = getWatchBaseline(ctx, manifestSource, manifestTarget, flavorsConfig)
+		}
+		if err == nil {
+			err = watchOnce(ctx, flavorsConfig, publishingConfig, aliasesConfig, creds, manifestSource, manifestTarget, constraint,
+				baseline, opts)
+		}
+		if err != nil {
+			if opts.Once {
+				return err
+			}
+			log.Error(ctx, err)
+		}
+		if opts.Once {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info(ctx, "Stopped watching")
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+
+	err = closeSourcesAndTargets(sources, targets, ocmTarget)
+	if err != nil {
+		return fmt.Errorf("cannot close sources and targets: %w", err)
+	}
+
+	return nil
+}
+
+// watchOnce publishes all complete releases which have not been handled yet. Releases which are part of the baseline are recorded as
+// skipped instead.
+func watchOnce(ctx context.Context, flavorsConfig FlavorsConfig, publishingConfig PublishingConfig, aliasesConfig AliasesConfig,
+	creds Credentials, manifestSource, manifestTarget cloudprovider.ArtifactSource, constraint *semver.Constraints,
+	baseline map[string]struct{}, opts WatchOptions,
+) error {
+	releases, err := findCompleteReleases(ctx, manifestSource, flavorsConfig)
+	if err != nil {
+		return err
+	}
+
+	var records map[string]WatchRecord
+	records, err = getWatchRecords(ctx, manifestTarget)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, release := range releases {
+		lctx := log.WithValues(ctx, "version", release.version, "commit", release.commit)
+		if constraint != nil {
+			ver, verr := semver.NewVersion(release.version)
+			if verr != nil || !constraint.Check(ver) {
+				continue
+			}
+		}
+
+		key := watchKey(release.version, release.commit)
+		record, ok := records[key]
+		if ok && (record.State != WatchStateFailed || record.Attempts >= opts.MaxAttempts) {
+			continue
+		}
+		record.Version = release.version
+		record.Commit = release.commit
+
+		_, existing := baseline[key]
+		if existing && !ok {
+			log.Info(lctx, "Skipping existing release")
+			record.State = WatchStateSkipped
+			record.Handled = time.Now().UTC().Truncate(time.Second)
+			err = putWatchRecord(lctx, manifestTarget, record)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		log.Info(lctx, "Publishing new release", "attempt", record.Attempts+1)
+		err = Publish(ctx, flavorsConfig, publishingConfig, aliasesConfig, creds, release.version, release.commit, opts.Publish)
+		if ctx.Err() != nil {
+			return errors.Join(err, context.Cause(ctx))
+		}
+		if errors.As(err, &LockedError{}) {
+			log.Info(lctx, "Release is locked by another run, retrying later")
+			continue
+		}
+
+		record.Attempts++
+		record.Handled = time.Now().UTC().Truncate(time.Second)
+		record.State = WatchStatePublished
+		record.Error = ""
+		if err != nil {
+			record.State = WatchStateFailed
+			record.Error = err.Error()
+			err = fmt.Errorf("cannot publish release %s-%.8s: %w", release.version, release.commit, err)
+			log.Error(lctx, err, "attempt", record.Attempts)
+			errs = append(errs, err)
+			if record.Attempts >= opts.MaxAttempts {
+				log.Info(lctx, "Giving up on release", "attempts", record.Attempts)
+			}
+		}
+
+		err = putWatchRecord(lctx, manifestTarget, record)
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// findCompleteReleases finds all releases for which the manifests of all configured flavors are present, the oldest first. Releases are
+// identified by the keys of their manifests, versions containing dashes are not supported.
+func findCompleteReleases(ctx context.Context, source cloudprovider.ArtifactSource, flavorsConfig FlavorsConfig) ([]watchedRelease,
+	error,
+) {
+	log.Debug(ctx, "Listing manifests")
+	objects, err := source.ListObjects(ctx, manifestPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list manifests: %w", err)
+	}
+
+	releases := make(map[string]*watchedRelease)
+	flavors := make(map[string]map[string]struct{})
+	for _, object := range objects {
+		for _, flavor := range flavorsConfig.Flavors {
+			rest, ok := strings.CutPrefix(object.Key, manifestPrefix+flavor.Cname+"-")
+			if !ok {
+				continue
+			}
+			version, commit, ok := strings.Cut(rest, "-")
+			if !ok || version == "" || len(commit) != 8 || strings.ContainsAny(commit, "-/") || strings.Contains(version, "/") {
+				continue
+			}
+
+			id := watchKey(version, commit)
+			release, found := releases[id]
+			if !found {
+				release = &watchedRelease{
+					version: version,
+					commit:  commit,
+				}
+				releases[id] = release
+				flavors[id] = make(map[string]struct{}, len(flavorsConfig.Flavors))
+			}
+			flavors[id][flavor.Cname] = struct{}{}
+			if object.LastModified.After(release.modified) {
+				release.modified = object.LastModified
+			}
+		}
+	}
+
+	complete := make([]watchedRelease, 0, len(releases))
+	for id, release := range releases {
+		if len(flavors[id]) < len(flavorsConfig.Flavors) {
+			log.Debug(ctx, "Release is incomplete", "version", release.version, "commit", release.commit, "flavors", len(flavors[id]),
+				"expected", len(flavorsConfig.Flavors))
+			continue
+		}
+		complete = append(complete, *release)
+	}
+	slices.SortFunc(complete, func(a, b watchedRelease) int {
+		return cmp.Or(a.modified.Compare(b.modified), strings.Compare(a.version, b.version), strings.Compare(a.commit, b.commit))
+	})
+
+	return complete, nil
+}
+
+func watchKey(version, commit string) string {
+	return fmt.Sprintf("%s%s-%.8s", watchPrefix, version, commit)
+}
+
+// getWatchBaseline returns the releases which were present when watching started for the first time. The baseline is established once
+// and persisted before any release is recorded, so that a first poll which fails halfway cannot cause the remaining existing releases to
+// be published later. Watching which has recorded releases before baselines were persisted has an empty baseline.
+func getWatchBaseline(ctx context.Context, manifestSource, manifestTarget cloudprovider.ArtifactSource, flavorsConfig FlavorsConfig,
+) (map[string]struct{}, error) {
+	var baseline watchBaseline
+	body, err := manifestTarget.GetObject(ctx, watchBaselineKey)
+	switch {
+	case err == nil:
+		err = yaml.NewDecoder(body).Decode(&baseline)
+		_ = body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid watch baseline %s: %w", watchBaselineKey, err)
+		}
+	case errors.As(err, &cloudprovider.KeyNotFoundError{}):
+		var records map[string]WatchRecord
+		records, err = getWatchRecords(ctx, manifestTarget)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > 0 {
+			return make(map[string]struct{}), nil
+		}
+
+		var releases []watchedRelease
+		releases, err = findCompleteReleases(ctx, manifestSource, flavorsConfig)
+		if err != nil {
+			return nil, err
+		}
+		baseline.Created = time.Now().UTC().Truncate(time.Second)
+		for _, release := range releases {
+			baseline.Releases = append(baseline.Releases, watchKey(release.version, release.commit))
+		}
+
+		log.Info(ctx, "Establishing baseline of existing releases", "count", len(baseline.Releases))
+		var buf bytes.Buffer
+		err = yaml.NewEncoder(&buf).Encode(baseline)
+		if err != nil {
+			return nil, fmt.Errorf("invalid watch baseline: %w", err)
+		}
+		err = manifestTarget.PutObject(ctx, watchBaselineKey, &buf)
+		if err != nil {
+			return nil, fmt.Errorf("cannot put watch baseline %s: %w", watchBaselineKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("cannot get watch baseline %s: %w", watchBaselineKey, err)
+	}
+
+	releases := make(map[string]struct{}, len(baseline.Releases))
+	for _, release := range baseline.Releases {
+		releases[release] = struct{}{}
+	}
+	return releases, nil
+}
+
+func getWatchRecords(ctx context.Context, source cloudprovider.ArtifactSource) (map[string]WatchRecord, error) {
+	objects, err := source.ListObjects(ctx, watchPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list watch records: %w", err)
+	}
+
+	records := make(map[string]WatchRecord, len(objects))
+	for _, object := range objects {
+		var body io.ReadCloser
+		body, err = source.GetObject(ctx, object.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get watch record %s: %w", object.Key, err)
+		}
+
+		var record WatchRecord
+		err = yaml.NewDecoder(body).Decode(&record)
+		_ = body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid watch record %s: %w", object.Key, err)
+		}
+		records[object.Key] = record
+	}
+
+	return records, nil
+}
+
+func putWatchRecord(ctx context.Context, source cloudprovider.ArtifactSource, record WatchRecord) error {
+	var buf bytes.Buffer
+	err := yaml.NewEncoder(&buf).Encode(record)
+	if err != nil {
+		return fmt.Errorf("invalid watch record: %w", err)
+	}
+
+	key := watchKey(record.Version, record.Commit)
+	err = source.PutObject(ctx, key, &buf)
+	if err != nil {
+		return fmt.Errorf("cannot put watch record %s: %w", key, err)
+	}
+
+	return nil
+}
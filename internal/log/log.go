@@ -31,6 +31,23 @@ func Setup(ctx context.Context, dev, silent bool, writer io.Writer) context.Cont
 	return logr.NewContext(ctx, zerologr.New(&zeroLog))
 }
 
+// Tee attaches a new logger to an existing context which logs to the existing logger as well as to the given writer, as JSON lines.
+func Tee(ctx context.Context, dev bool, writer io.Writer) context.Context {
+	level := zerolog.InfoLevel
+	if dev {
+		level = zerolog.DebugLevel
+	}
+	zeroLog := zerolog.New(writer).Level(level).With().Timestamp().Logger()
+
+	sinks := []logr.LogSink{zerologr.New(&zeroLog).GetSink()}
+	existing := logr.FromContextOrDiscard(ctx).GetSink()
+	if existing != nil {
+		sinks = append(sinks, existing)
+	}
+
+	return logr.NewContext(ctx, logr.New(teeSink(sinks)))
+}
+
 // Info logs an information message.
 func Info(ctx context.Context, msg string, keysAndValues ...any) {
 	logr.FromContextOrDiscard(ctx).Info(msg, keysAndValues...)
@@ -50,3 +67,53 @@ func Error(ctx context.Context, err error, keysAndValues ...any) {
 func WithValues(ctx context.Context, keysAndValues ...any) context.Context {
 	return logr.NewContext(ctx, logr.FromContextOrDiscard(ctx).WithValues(keysAndValues...))
 }
+
+type teeSink []logr.LogSink
+
+func (t teeSink) Init(info logr.RuntimeInfo) {
+	for _, sink := range t {
+		sink.Init(info)
+	}
+}
+
+func (t teeSink) Enabled(level int) bool {
+	for _, sink := range t {
+		if sink.Enabled(level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t teeSink) Info(level int, msg string, keysAndValues ...any) {
+	for _, sink := range t {
+		if sink.Enabled(level) {
+			sink.Info(level, msg, keysAndValues...)
+		}
+	}
+}
+
+func (t teeSink) Error(err error, msg string, keysAndValues ...any) {
+	for _, sink := range t {
+		sink.Error(err, msg, keysAndValues...)
+	}
+}
+
+func (t teeSink) WithValues(keysAndValues ...any) logr.LogSink {
+	sinks := make(teeSink, 0, len(t))
+	for _, sink := range t {
+		sinks = append(sinks, sink.WithValues(keysAndValues...))
+	}
+
+	return sinks
+}
+
+func (t teeSink) WithName(name string) logr.LogSink {
+	sinks := make(teeSink, 0, len(t))
+	for _, sink := range t {
+		sinks = append(sinks, sink.WithName(name))
+	}
+
+	return sinks
+}
@@ -208,6 +208,33 @@ func AddPublicationOutput(descriptor *ComponentDescriptor, publications []cloudp
 	return nil
 }
 
+// RetainPublications removes the resources of all publications that are not to be retained from a component descriptor that has been
+// built for these publications. It returns the retained publications.
+func RetainPublications(descriptor *ComponentDescriptor, publications []cloudprovider.Publication, retain []bool,
+) ([]cloudprovider.Publication, error) {
+	if len(descriptor.Component.Resources) != len(publications)*2 {
+		return nil, fmt.Errorf("invalid component descriptor: expected %d resources, got %d", len(publications)*2,
+			len(descriptor.Component.Resources))
+	}
+	if len(retain) != len(publications) {
+		return nil, fmt.Errorf("invalid retention: expected %d publications, got %d", len(publications), len(retain))
+	}
+
+	retained := make([]cloudprovider.Publication, 0, len(publications))
+	resources := make([]componentDesciptorResource, 0, len(descriptor.Component.Resources))
+	for i, publication := range publications {
+		if !retain[i] {
+			continue
+		}
+
+		retained = append(retained, publication)
+		resources = append(resources, descriptor.Component.Resources[i*2], descriptor.Component.Resources[i*2+1])
+	}
+	descriptor.Component.Resources = resources
+
+	return retained, nil
+}
+
 const (
 	componentProvider = "sap-se"
 	githubRepoURL     = "https://" + gl.GardenLinuxRepo
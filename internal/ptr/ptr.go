@@ -2,3 +2,13 @@ package ptr
 
 // P returns a pointer to any avlue, including a literal.
 func P[T any](t T) *T { return &t }
+
+// V returns the value a pointer points to or the zero value if the pointer is nil.
+func V[T any](p *T) T {
+	if p == nil {
+		var t T
+		return t
+	}
+
+	return *p
+}
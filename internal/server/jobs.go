@@ -0,0 +1,370 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gardenlinux/glci/internal/glci"
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+// JobKind is the operation a job carries out.
+type JobKind string
+
+const (
+	// JobKindPublish publishes a release.
+	JobKindPublish JobKind = "publish"
+	// JobKindRemove removes a release.
+	JobKindRemove JobKind = "remove"
+	// JobKindStatus determines the publication state of a release.
+	JobKindStatus JobKind = "status"
+)
+
+// JobState is the lifecycle state of a job.
+type JobState string
+
+const (
+	// JobStateQueued means that the job waits for an earlier job of the same release to finish.
+	JobStateQueued JobState = "queued"
+	// JobStateRunning means that the job is in progress.
+	JobStateRunning JobState = "running"
+	// JobStateSucceeded means that the job has finished successfully.
+	JobStateSucceeded JobState = "succeeded"
+	// JobStateFailed means that the job has finished with an error.
+	JobStateFailed JobState = "failed"
+	// JobStateCancelled means that the job has been cancelled before it could finish.
+	JobStateCancelled JobState = "cancelled"
+)
+
+// JobRequest describes a job to be submitted.
+type JobRequest struct {
+	Kind              JobKind        `json:"kind"`
+	Version           string         `json:"version"`
+	Commit            string         `json:"commit,omitempty"`
+	Parallelism       int            `json:"parallelism,omitempty"`
+	KeepGoing         bool           `json:"keep_going,omitempty"`
+	PartialDescriptor bool           `json:"partial_descriptor,omitempty"`
+	KeepLeftovers     bool           `json:"keep_leftovers,omitempty"`
+	Selection         glci.Selection `json:"selection"`
+}
+
+// Job is a snapshot of a submitted job.
+type Job struct {
+	ID       string     `json:"id"`
+	Kind     JobKind    `json:"kind"`
+	Version  string     `json:"version"`
+	Commit   string     `json:"commit,omitempty"`
+	State    JobState   `json:"state"`
+	Error    string     `json:"error,omitempty"`
+	Created  time.Time  `json:"created"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// JobResult is the outcome of a finished job. Status contains the images per publishing target as they are after the job, it is missing
+// if the job has failed before the release could be inspected.
+type JobResult struct {
+	Job    Job                 `json:"job"`
+	Status *glci.ReleaseStatus `json:"status,omitempty"`
+}
+
+// Config is the configuration and the credentials all jobs are run with. They are loaded once when the server starts.
+type Config struct {
+	Flavors    glci.FlavorsConfig
+	Publishing glci.PublishingConfig
+	Aliases    glci.AliasesConfig
+	Creds      glci.Credentials
+	Dev        bool
+}
+
+const (
+	// retainedJobs is the maximum number of finished jobs which are kept for inspection, older ones are evicted along with their logs.
+	retainedJobs = 100
+	// jobRetention is how long a finished job is kept for inspection at most.
+	jobRetention = 24 * time.Hour
+)
+
+var (
+	errJobNotFound    = errors.New("job not found")
+	errJobNotFinished = errors.New("job has not finished yet")
+)
+
+type job struct {
+	mutex  sync.Mutex
+	job    Job
+	req    JobRequest
+	status *glci.ReleaseStatus
+	logs   jobLog
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (j *job) snapshot() Job {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.job
+}
+
+func (j *job) setState(state JobState, err error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	now := time.Now().UTC()
+	j.job.State = state
+	switch state {
+	case JobStateRunning:
+		j.job.Started = &now
+	case JobStateSucceeded, JobStateFailed, JobStateCancelled:
+		j.job.Finished = &now
+	case JobStateQueued:
+	}
+	if err != nil {
+		j.job.Error = err.Error()
+	}
+}
+
+// queue runs jobs in the background. Jobs of the same release version are run one after another in the order they have been submitted,
+// jobs of different versions run concurrently.
+type queue struct {
+	ctx     context.Context
+	cfg     Config
+	mutex   sync.Mutex
+	jobs    map[string]*job
+	order   []string
+	last    map[string]chan struct{}
+	running sync.WaitGroup
+}
+
+func newQueue(ctx context.Context, cfg Config) *queue {
+	return &queue{
+		ctx:  ctx,
+		cfg:  cfg,
+		jobs: make(map[string]*job),
+		last: make(map[string]chan struct{}),
+	}
+}
+
+func (q *queue) submit(req JobRequest) (Job, error) {
+	switch req.Kind {
+	case JobKindPublish, JobKindRemove, JobKindStatus:
+	default:
+		return Job{}, fmt.Errorf("unknown job kind %s", req.Kind)
+	}
+	if req.Version == "" {
+		return Job{}, errors.New("missing version")
+	}
+	if req.Parallelism < 0 {
+		return Job{}, fmt.Errorf("invalid parallelism: %d", req.Parallelism)
+	}
+
+	ctx, cancel := context.WithCancel(q.ctx)
+	j := &job{
+		job: Job{
+			ID:      rand.Text(),
+			Kind:    req.Kind,
+			Version: req.Version,
+			Commit:  req.Commit,
+			State:   JobStateQueued,
+			Created: time.Now().UTC(),
+		},
+		req:    req,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	j.logs.init()
+
+	q.mutex.Lock()
+	q.evict(time.Now())
+	prev := q.last[req.Version]
+	q.last[req.Version] = j.done
+	q.jobs[j.job.ID] = j
+	q.order = append(q.order, j.job.ID)
+	q.running.Add(1)
+	q.mutex.Unlock()
+
+	go q.run(ctx, j, prev)
+
+	return j.snapshot(), nil
+}
+
+func (q *queue) run(ctx context.Context, j *job, prev chan struct{}) {
+	defer q.running.Done()
+	defer q.forget(j)
+	defer close(j.done)
+	defer j.logs.close()
+	defer j.cancel()
+
+	ctx = log.Tee(ctx, q.cfg.Dev, &j.logs)
+	ctx = log.WithValues(ctx, "job", j.job.ID)
+
+	if prev != nil {
+		log.Info(ctx, "Waiting for earlier job of the same release")
+		select {
+		case <-ctx.Done():
+			log.Info(ctx, "Job cancelled")
+			j.setState(JobStateCancelled, nil)
+			return
+		case <-prev:
+		}
+	}
+
+	j.setState(JobStateRunning, nil)
+	log.Info(ctx, "Job started", "kind", j.req.Kind, "version", j.req.Version, "commit", j.req.Commit)
+	status, err := q.execute(ctx, j.req)
+
+	j.mutex.Lock()
+	j.status = status
+	j.mutex.Unlock()
+
+	switch {
+	case err == nil:
+		log.Info(ctx, "Job succeeded")
+		j.setState(JobStateSucceeded, nil)
+	case ctx.Err() != nil:
+		log.Info(ctx, "Job cancelled")
+		j.setState(JobStateCancelled, err)
+	default:
+		log.Error(ctx, err)
+		j.setState(JobStateFailed, err)
+	}
+}
+
+// execute carries out the operation of a job and determines the publication state of the release afterwards.
+func (q *queue) execute(ctx context.Context, req JobRequest) (*glci.ReleaseStatus, error) {
+	opts := glci.Options{
+		Parallelism:       max(req.Parallelism, 1),
+		KeepGoing:         req.KeepGoing,
+		PartialDescriptor: req.PartialDescriptor,
+		KeepLeftovers:     req.KeepLeftovers,
+		Selection:         req.Selection,
+	}
+
+	var err error
+	switch req.Kind {
+	case JobKindPublish:
+		err = glci.Publish(ctx, q.cfg.Flavors, q.cfg.Publishing, q.cfg.Aliases, q.cfg.Creds, req.Version, req.Commit, opts)
+	case JobKindRemove:
+		err = glci.Remove(ctx, q.cfg.Flavors, q.cfg.Publishing, q.cfg.Creds, req.Version, req.Commit, opts)
+	case JobKindStatus:
+	}
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Directly wraps the GLCI command.
+	}
+
+	var status glci.ReleaseStatus
+	status, err = glci.Status(ctx, q.cfg.Flavors, q.cfg.Publishing, q.cfg.Creds, req.Version, req.Commit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// forget stops tracking a finished job as the last one of its release.
+func (q *queue) forget(j *job) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.last[j.req.Version] == j.done {
+		delete(q.last, j.req.Version)
+	}
+	q.evict(time.Now())
+}
+
+// evict drops finished jobs which exceed the retention limits, so that their logs do not accumulate in a long-running server. Jobs which
+// have not finished yet are always kept. The queue must be locked.
+func (q *queue) evict(now time.Time) {
+	var finished int
+	order := make([]string, 0, len(q.order))
+	for i := len(q.order) - 1; i >= 0; i-- {
+		id := q.order[i]
+		snapshot := q.jobs[id].snapshot()
+		if snapshot.Finished != nil {
+			finished++
+			if finished > retainedJobs || now.Sub(*snapshot.Finished) > jobRetention {
+				delete(q.jobs, id)
+				continue
+			}
+		}
+		order = append(order, id)
+	}
+	slices.Reverse(order)
+	q.order = order
+}
+
+func (q *queue) get(id string) (*job, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	j, ok := q.jobs[id]
+	if !ok {
+		return nil, errJobNotFound
+	}
+
+	return j, nil
+}
+
+func (q *queue) list() []Job {
+	q.mutex.Lock()
+	q.evict(time.Now())
+	ids := slices.Clone(q.order)
+	q.mutex.Unlock()
+
+	jobs := make([]Job, 0, len(ids))
+	for _, id := range ids {
+		j, err := q.get(id)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, j.snapshot())
+	}
+
+	return jobs
+}
+
+func (q *queue) cancel(id string) (Job, error) {
+	j, err := q.get(id)
+	if err != nil {
+		return Job{}, err
+	}
+	j.cancel()
+
+	return j.snapshot(), nil
+}
+
+func (q *queue) result(id string) (JobResult, error) {
+	j, err := q.get(id)
+	if err != nil {
+		return JobResult{}, err
+	}
+	select {
+	case <-j.done:
+	default:
+		return JobResult{}, errJobNotFinished
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return JobResult{
+		Job:    j.job,
+		Status: j.status,
+	}, nil
+}
+
+// shutdown cancels all jobs and waits for them to finish, so that they can release their locks.
+func (q *queue) shutdown() {
+	q.mutex.Lock()
+	for _, j := range q.jobs {
+		j.cancel()
+	}
+	q.mutex.Unlock()
+
+	q.running.Wait()
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// jobLog collects the log of a job as JSON lines and lets any number of readers follow it while the job is running.
+type jobLog struct {
+	mutex   sync.Mutex
+	buf     []byte
+	changed chan struct{}
+	closed  bool
+}
+
+func (l *jobLog) init() {
+	l.changed = make(chan struct{})
+}
+
+func (l *jobLog) Write(p []byte) (int, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return 0, io.ErrClosedPipe
+	}
+	l.buf = append(l.buf, p...)
+	close(l.changed)
+	l.changed = make(chan struct{})
+
+	return len(p), nil
+}
+
+func (l *jobLog) close() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return
+	}
+	l.closed = true
+	close(l.changed)
+}
+
+// follow writes the log to w from its beginning. If follow is set, it keeps waiting for more until the log is closed or the context is
+// done.
+func (l *jobLog) follow(ctx context.Context, w io.Writer, flush func(), follow bool) error {
+	var offset int
+	for {
+		l.mutex.Lock()
+		chunk := l.buf[offset:len(l.buf):len(l.buf)]
+		changed := l.changed
+		closed := l.closed
+		l.mutex.Unlock()
+
+		if len(chunk) > 0 {
+			_, err := w.Write(chunk)
+			if err != nil {
+				return err //nolint:wrapcheck // Directly wraps the writer.
+			}
+			flush()
+			offset += len(chunk)
+		}
+		if closed || !follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
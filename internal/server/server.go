@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gardenlinux/glci/internal/log"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// Serve runs the HTTP API on the given address until the context is done. Jobs are run with the given configuration and credentials.
+// If a token is given, every request must carry it as a bearer token. When the context is done, all jobs are cancelled and waited for.
+func Serve(ctx context.Context, addr, token string, cfg Config) error {
+	ctx = log.WithValues(ctx, "op", "serve")
+
+	q := newQueue(ctx, cfg)
+	defer q.shutdown()
+
+	s := &http.Server{
+		Addr:              addr,
+		Handler:           authenticated(token, routes(q)),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Info(ctx, "Serving HTTP API", "addr", addr)
+		errs <- s.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("cannot serve HTTP API: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Info(ctx, "Shutting down")
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	err := s.Shutdown(sctx)
+	if err != nil {
+		return fmt.Errorf("cannot shut down HTTP API: %w", err)
+	}
+
+	return nil
+}
+
+func routes(q *queue) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("GET /jobs", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(r.Context(), w, http.StatusOK, q.list())
+	})
+
+	mux.HandleFunc("POST /jobs", func(w http.ResponseWriter, r *http.Request) {
+		var req JobRequest
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&req)
+		if err != nil {
+			writeError(r.Context(), w, http.StatusBadRequest, fmt.Errorf("invalid job: %w", err))
+			return
+		}
+
+		var job Job
+		job, err = q.submit(req)
+		if err != nil {
+			writeError(r.Context(), w, http.StatusBadRequest, fmt.Errorf("invalid job: %w", err))
+			return
+		}
+		log.Info(r.Context(), "Job submitted", "job", job.ID, "kind", job.Kind, "version", job.Version, "commit", job.Commit)
+
+		w.Header().Set("Location", "/jobs/"+job.ID)
+		writeJSON(r.Context(), w, http.StatusAccepted, job)
+	})
+
+	mux.HandleFunc("GET /jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		j, err := q.get(r.PathValue("id"))
+		if err != nil {
+			writeError(r.Context(), w, http.StatusNotFound, err)
+			return
+		}
+
+		writeJSON(r.Context(), w, http.StatusOK, j.snapshot())
+	})
+
+	mux.HandleFunc("POST /jobs/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		job, err := q.cancel(r.PathValue("id"))
+		if err != nil {
+			writeError(r.Context(), w, http.StatusNotFound, err)
+			return
+		}
+		log.Info(r.Context(), "Job cancellation requested", "job", job.ID)
+
+		writeJSON(r.Context(), w, http.StatusAccepted, job)
+	})
+
+	mux.HandleFunc("GET /jobs/{id}/result", func(w http.ResponseWriter, r *http.Request) {
+		result, err := q.result(r.PathValue("id"))
+		switch {
+		case errors.Is(err, errJobNotFound):
+			writeError(r.Context(), w, http.StatusNotFound, err)
+		case errors.Is(err, errJobNotFinished):
+			writeError(r.Context(), w, http.StatusConflict, err)
+		case err != nil:
+			writeError(r.Context(), w, http.StatusInternalServerError, err)
+		default:
+			writeJSON(r.Context(), w, http.StatusOK, result)
+		}
+	})
+
+	mux.HandleFunc("GET /jobs/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		j, err := q.get(r.PathValue("id"))
+		if err != nil {
+			writeError(r.Context(), w, http.StatusNotFound, err)
+			return
+		}
+		var follow bool
+		followParam := r.URL.Query().Get("follow")
+		if followParam != "" {
+			follow, err = strconv.ParseBool(followParam)
+			if err != nil {
+				writeError(r.Context(), w, http.StatusBadRequest, fmt.Errorf("invalid follow parameter: %w", err))
+				return
+			}
+		}
+
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		err = j.logs.follow(r.Context(), w, func() {
+			_ = rc.Flush()
+		}, follow)
+		if err != nil {
+			log.Debug(r.Context(), "Cannot stream job log", "job", j.job.ID, "error", err.Error())
+		}
+	})
+
+	return mux
+}
+
+func authenticated(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				writeError(r.Context(), w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(ctx context.Context, w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(data)
+	if err != nil {
+		log.Debug(ctx, "Cannot write response", "error", err.Error())
+	}
+}
+
+func writeError(ctx context.Context, w http.ResponseWriter, status int, err error) {
+	writeJSON(ctx, w, status, struct {
+		Error string `json:"error"`
+	}{
+		Error: err.Error(),
+	})
+}